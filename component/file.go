@@ -0,0 +1,19 @@
+package component
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// 写入文件，目录不存在时创建；先写临时文件再替换，避免写一半
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,186 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+)
+
+// ip 名单
+const (
+	IpAllow = "allow"
+	IpDeny  = "deny"
+)
+
+// IpFilter ip 黑白名单，每一项是 ip 或者 CIDR 网段，支持 ipv4 和 ipv6
+// 白名单优先，可以在黑名单的网段中放行个别地址；每次修改后保存到 json 文件，重启后恢复
+type IpFilter struct {
+	lock  sync.RWMutex
+	path  string
+	lists map[string][]*net.IPNet
+}
+
+// 保存的文件格式
+type ipFilterFile struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// NewIpFilter 只保存在内存中的名单
+func NewIpFilter() *IpFilter {
+	return &IpFilter{
+		lists: map[string][]*net.IPNet{},
+	}
+}
+
+// OpenIpFilter 读取名单，路径为空时不保存
+func OpenIpFilter(path string) (*IpFilter, error) {
+	f := NewIpFilter()
+	f.path = path
+	if path == "" {
+		return f, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data := &ipFilterFile{}
+	if err := json.Unmarshal(b, data); err != nil {
+		return nil, err
+	}
+	for list, entries := range map[string][]string{IpAllow: data.Allow, IpDeny: data.Deny} {
+		for _, entry := range entries {
+			n, err := ParseIpNet(entry)
+			if err != nil {
+				return nil, err
+			}
+			f.lists[list] = append(f.lists[list], n)
+		}
+	}
+
+	return f, nil
+}
+
+// ParseIpNet 解析 ip 或者 CIDR 网段，单个 ip 按只包含它自己的网段处理
+func ParseIpNet(entry string) (*net.IPNet, error) {
+	if _, n, err := net.ParseCIDR(entry); err == nil {
+		return n, nil
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip or cidr %q", entry)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Allowed ip 在白名单中
+func (f *IpFilter) Allowed(ip net.IP) bool {
+	return f.match(IpAllow, ip)
+}
+
+// Denied ip 在黑名单中并且不在白名单中
+func (f *IpFilter) Denied(ip net.IP) bool {
+	return !f.match(IpAllow, ip) && f.match(IpDeny, ip)
+}
+
+func (f *IpFilter) match(list string, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	for _, n := range f.lists[list] {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add 加入名单，已经存在的不重复添加
+func (f *IpFilter) Add(list string, entry string) error {
+	if list != IpAllow && list != IpDeny {
+		return fmt.Errorf("unknown list %q", list)
+	}
+	n, err := ParseIpNet(entry)
+	if err != nil {
+		return err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, old := range f.lists[list] {
+		if old.String() == n.String() {
+			return nil
+		}
+	}
+	f.lists[list] = append(f.lists[list], n)
+	return f.save()
+}
+
+// Remove 移出名单
+func (f *IpFilter) Remove(list string, entry string) error {
+	if list != IpAllow && list != IpDeny {
+		return fmt.Errorf("unknown list %q", list)
+	}
+	n, err := ParseIpNet(entry)
+	if err != nil {
+		return err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	entries := f.lists[list][:0]
+	for _, old := range f.lists[list] {
+		if old.String() != n.String() {
+			entries = append(entries, old)
+		}
+	}
+	f.lists[list] = entries
+	return f.save()
+}
+
+// Lists 当前名单
+func (f *IpFilter) Lists() (allow []string, deny []string) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	data := f.file()
+	return data.Allow, data.Deny
+}
+
+func (f *IpFilter) file() *ipFilterFile {
+	data := &ipFilterFile{
+		Allow: []string{},
+		Deny:  []string{},
+	}
+	for _, n := range f.lists[IpAllow] {
+		data.Allow = append(data.Allow, n.String())
+	}
+	for _, n := range f.lists[IpDeny] {
+		data.Deny = append(data.Deny, n.String())
+	}
+	return data
+}
+
+// 保存名单
+func (f *IpFilter) save() error {
+	if f.path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(f.file(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.path, b)
+}
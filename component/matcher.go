@@ -0,0 +1,171 @@
+package component
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Match 一处命中，Start、End 是原文中的字节偏移，命中的内容是 text[Start:End]
+type Match struct {
+	Start int
+	End   int
+	Word  string // 命中的敏感词
+}
+
+// Matcher 敏感词匹配，建好之后只读，可以在多个协程中使用
+type Matcher interface {
+	// FindAll 返回所有命中，包括互相重叠的，按起始位置排序
+	FindAll(text string) []Match
+}
+
+// AhoCorasick 多模式匹配自动机，按字符（rune）匹配，扫描一遍文本找出所有敏感词
+type AhoCorasick struct {
+	nodes []acNode
+	words []string
+}
+
+type acNode struct {
+	next   map[rune]int32
+	fail   int32 // 失配时跳转的节点：当前路径的最长后缀所在的节点
+	output int32 // 后缀链上下一个是敏感词结尾的节点，没有为 0
+	word   int32 // 在这个节点结束的敏感词，没有为 -1
+	depth  int32 // 路径长度，字符数
+}
+
+// NewAhoCorasick 用敏感词建自动机，空的和重复的词忽略
+func NewAhoCorasick(words []string) *AhoCorasick {
+	ac := &AhoCorasick{
+		nodes: []acNode{{word: -1}},
+	}
+	for _, w := range words {
+		ac.insert(w)
+	}
+	ac.build()
+	return ac
+}
+
+func (ac *AhoCorasick) insert(w string) {
+	if w == "" {
+		return
+	}
+	cur := int32(0)
+	for _, r := range w {
+		node := &ac.nodes[cur]
+		if node.next == nil {
+			node.next = map[rune]int32{}
+		}
+		next, ok := node.next[r]
+		if !ok {
+			next = int32(len(ac.nodes))
+			node.next[r] = next
+			ac.nodes = append(ac.nodes, acNode{word: -1, depth: ac.nodes[cur].depth + 1})
+		}
+		cur = next
+	}
+	if ac.nodes[cur].word < 0 {
+		ac.nodes[cur].word = int32(len(ac.words))
+		ac.words = append(ac.words, w)
+	}
+}
+
+// 按层遍历，计算失配跳转和输出链
+func (ac *AhoCorasick) build() {
+	queue := make([]int32, 0, len(ac.nodes))
+	for _, child := range ac.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range ac.nodes[cur].next {
+			fail := ac.nodes[cur].fail
+			for {
+				if next, ok := ac.nodes[fail].next[r]; ok {
+					ac.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+			f := ac.nodes[child].fail
+			if ac.nodes[f].word >= 0 {
+				ac.nodes[child].output = f
+			} else {
+				ac.nodes[child].output = ac.nodes[f].output
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// 从 state 读入字符 r 后的状态
+func (ac *AhoCorasick) step(state int32, r rune) int32 {
+	for {
+		if next, ok := ac.nodes[state].next[r]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = ac.nodes[state].fail
+	}
+}
+
+// FindAll 扫描一遍文本
+func (ac *AhoCorasick) FindAll(text string) []Match {
+	var matches []Match
+	// 最近读入的字符的起始偏移，用来从字符数换算出命中的起始位置
+	var starts []int
+	state := int32(0)
+	for i := 0; i < len(text); {
+		// 非法的 utf8 字节按一个字符处理
+		r, size := utf8.DecodeRuneInString(text[i:])
+		starts = append(starts, i)
+		state = ac.step(state, r)
+		i += size
+		end := i
+		for n := state; n != 0; n = ac.nodes[n].output {
+			node := &ac.nodes[n]
+			if node.word < 0 {
+				continue
+			}
+			matches = append(matches, Match{
+				Start: starts[len(starts)-int(node.depth)],
+				End:   end,
+				Word:  ac.words[node.word],
+			})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// Mask 把命中的部分逐个字符替换成 mask，替换后字数不变，重叠的命中合并处理
+func Mask(text string, matches []Match, mask rune) string {
+	if len(matches) == 0 {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := 0
+	for _, m := range matches {
+		if m.End <= pos {
+			continue
+		}
+		if m.Start > pos {
+			b.WriteString(text[pos:m.Start])
+			pos = m.Start
+		}
+		for range text[pos:m.End] {
+			b.WriteRune(mask)
+		}
+		pos = m.End
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
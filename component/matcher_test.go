@@ -0,0 +1,198 @@
+package component
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	filter "github.com/antlinker/go-dirtyfilter"
+	"github.com/antlinker/go-dirtyfilter/store"
+)
+
+func TestAhoCorasickOverlaps(t *testing.T) {
+	cases := []struct {
+		words []string
+		text  string
+		want  []Match
+	}{
+		{
+			words: []string{"he", "she", "his", "hers"},
+			text:  "ushers",
+			want: []Match{
+				{Start: 1, End: 4, Word: "she"},
+				{Start: 2, End: 4, Word: "he"},
+				{Start: 2, End: 6, Word: "hers"},
+			},
+		},
+		{
+			words: []string{"中国", "国人", "中国人"},
+			text:  "我是中国人",
+			want: []Match{
+				{Start: 6, End: 12, Word: "中国"},
+				{Start: 6, End: 15, Word: "中国人"},
+				{Start: 9, End: 15, Word: "国人"},
+			},
+		},
+		{
+			// 同一个词连续出现
+			words: []string{"aa"},
+			text:  "aaa",
+			want: []Match{
+				{Start: 0, End: 2, Word: "aa"},
+				{Start: 1, End: 3, Word: "aa"},
+			},
+		},
+		{
+			// 空词和重复的词忽略
+			words: []string{"", "ab", "ab"},
+			text:  "xab",
+			want: []Match{
+				{Start: 1, End: 3, Word: "ab"},
+			},
+		},
+		{
+			words: []string{"abc"},
+			text:  "ab",
+		},
+	}
+	for _, c := range cases {
+		got := NewAhoCorasick(c.words).FindAll(c.text)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("FindAll(%q) with %q = %+v, want %+v", c.text, c.words, got, c.want)
+		}
+	}
+}
+
+func TestAhoCorasickInvalidUTF8(t *testing.T) {
+	cases := []struct {
+		words []string
+		text  string
+		want  []Match
+	}{
+		{
+			words: []string{"ab"},
+			text:  "x\xffab\xfe",
+			want: []Match{
+				{Start: 2, End: 4, Word: "ab"},
+			},
+		},
+		{
+			// 截断的汉字不影响后面的匹配
+			words: []string{"中国"},
+			text:  "\xe4\xb8中国",
+			want: []Match{
+				{Start: 2, End: 8, Word: "中国"},
+			},
+		},
+		{
+			// 非法字节按一个字符处理，偏移仍然是字节
+			words: []string{"a\xffb"},
+			text:  "\xfea\xffb",
+			want: []Match{
+				{Start: 1, End: 4, Word: "a\xffb"},
+			},
+		},
+	}
+	for _, c := range cases {
+		got := NewAhoCorasick(c.words).FindAll(c.text)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("FindAll(%q) with %q = %+v, want %+v", c.text, c.words, got, c.want)
+		}
+		for _, m := range got {
+			if c.text[m.Start:m.End] != m.Word {
+				t.Errorf("FindAll(%q): text[%d:%d] = %q, want %q", c.text, m.Start, m.End, c.text[m.Start:m.End], m.Word)
+			}
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	cases := []struct {
+		words []string
+		text  string
+		want  string
+	}{
+		{[]string{"he", "she", "hers"}, "ushers", "u*****"},
+		{[]string{"中国", "国人"}, "我是中国人啊", "我是***啊"},
+		{[]string{"ab", "cd"}, "abxcd", "**x**"},
+		{[]string{"ab"}, "x\xffab\xfe", "x\xff**\xfe"},
+		{[]string{"a\xffb"}, "a\xffbc", "***c"},
+		{[]string{"不存在"}, "没有命中", "没有命中"},
+	}
+	for _, c := range cases {
+		got := Mask(c.text, NewAhoCorasick(c.words).FindAll(c.text), '*')
+		if got != c.want {
+			t.Errorf("Mask(%q) with %q = %q, want %q", c.text, c.words, got, c.want)
+		}
+		// 替换后字数不变
+		if n, want := utf8.RuneCountInString(got), utf8.RuneCountInString(c.text); n != want {
+			t.Errorf("Mask(%q) with %q has %d characters, want %d", c.text, c.words, n, want)
+		}
+	}
+}
+
+// 随机生成的汉字词库和聊天消息，固定种子保证每次一样
+func benchData(words int) ([]string, []string) {
+	r := rand.New(rand.NewSource(1))
+	han := func(n int) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteRune(rune(0x4E00 + r.Intn(3000)))
+		}
+		return b.String()
+	}
+	list := make([]string, words)
+	for i := range list {
+		list[i] = han(2 + r.Intn(3))
+	}
+	texts := make([]string, 100)
+	for i := range texts {
+		// 一半的消息带一个敏感词
+		text := han(20 + r.Intn(40))
+		if i%2 == 0 {
+			text += list[r.Intn(len(list))] + han(10)
+		}
+		texts[i] = text
+	}
+	return list, texts
+}
+
+func benchmarkAhoCorasick(b *testing.B, words int) {
+	list, texts := benchData(words)
+	ac := NewAhoCorasick(list)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		text := texts[i%len(texts)]
+		_ = Mask(text, ac.FindAll(text), '*')
+	}
+}
+
+// 原来的实现：dirtyfilter 找出命中的词，再逐个 strings.ReplaceAll
+func benchmarkDirtyFilter(b *testing.B, words int) {
+	list, texts := benchData(words)
+	memStore, err := store.NewMemoryStore(store.MemoryConfig{
+		DataSource: list,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	manager := filter.NewDirtyManager(memStore)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		text := texts[i%len(texts)]
+		result, err := manager.Filter().Filter(text, '*', '@')
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, w := range result {
+			text = strings.ReplaceAll(text, w, "*")
+		}
+	}
+}
+
+func BenchmarkAhoCorasick10k(b *testing.B)  { benchmarkAhoCorasick(b, 10000) }
+func BenchmarkAhoCorasick100k(b *testing.B) { benchmarkAhoCorasick(b, 100000) }
+func BenchmarkDirtyFilter10k(b *testing.B)  { benchmarkDirtyFilter(b, 10000) }
+func BenchmarkDirtyFilter100k(b *testing.B) { benchmarkDirtyFilter(b, 100000) }
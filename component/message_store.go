@@ -0,0 +1,235 @@
+package component
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// ChatRecord 一条聊天记录，保存的是过滤后的内容
+type ChatRecord struct {
+	Id      int64     `json:"id"`
+	Time    time.Time `json:"time"`
+	Room    string    `json:"room"`
+	BotId   string    `json:"bot_id"`
+	Name    string    `json:"name"`
+	Msg     string    `json:"msg"`
+	PosInfo *pb.PInfo `json:"pos_info"`
+	X       float64   `json:"x"` // 发送时的世界坐标
+	Y       float64   `json:"y"`
+	Shout   bool      `json:"shout"`
+}
+
+// MessageStore 聊天记录存储
+type MessageStore interface {
+	// Append 保存一条记录，分配自增 id
+	Append(record *ChatRecord) error
+	// Recent 房间最近的 n 条记录，按时间先后排列
+	Recent(room string, n int) []*ChatRecord
+	// Query 按条件查询，从新到旧排列，q.Oldest 为 true 时从旧到新
+	Query(q *MessageQuery) ([]*ChatRecord, error)
+	Close() error
+}
+
+// MessageQuery 查询条件，零值表示不限制
+type MessageQuery struct {
+	Before  int64     // 游标，只返回 id 小于它的记录
+	After   int64     // 只返回 id 大于它的记录
+	Since   time.Time // 开始时间，包含
+	Until   time.Time // 结束时间，不包含
+	Room    string
+	BotId   string
+	Name    string
+	Keyword string // 消息内容包含的字符串
+	Limit   int
+	Oldest  bool // 从旧到新，只返回最早的 Limit 条，默认返回最新的 Limit 条
+}
+
+// Match 记录是否符合条件
+func (q *MessageQuery) Match(r *ChatRecord) bool {
+	switch {
+	case q.Before > 0 && r.Id >= q.Before:
+		return false
+	case q.After > 0 && r.Id <= q.After:
+		return false
+	case !q.Since.IsZero() && r.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !r.Time.Before(q.Until):
+		return false
+	case q.Room != "" && r.Room != q.Room:
+		return false
+	case q.BotId != "" && r.BotId != q.BotId:
+		return false
+	case q.Name != "" && r.Name != q.Name:
+		return false
+	case q.Keyword != "" && !strings.Contains(r.Msg, q.Keyword):
+		return false
+	}
+	return true
+}
+
+// FileMessageStore 追加写入的文件存储，每行一条 json
+// 每个房间最近的记录保留在内存中，用于新用户进入时回放
+type FileMessageStore struct {
+	lock   sync.Mutex
+	path   string
+	file   *os.File
+	lastId int64
+	keep   int                      // 每个房间在内存中保留的记录数
+	recent map[string][]*ChatRecord // 房间 => 最近的记录
+}
+
+// OpenFileMessageStore 打开文件存储，读取已有记录恢复 id 和最近的记录
+func OpenFileMessageStore(path string, keep int) (*FileMessageStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileMessageStore{
+		path:   path,
+		file:   file,
+		keep:   keep,
+		recent: map[string][]*ChatRecord{},
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		record := &ChatRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			// 写了一半的行，跳过
+			continue
+		}
+		s.remember(record)
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	// 上次写了一半的行补上换行，不影响之后的记录
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			_, _ = file.Write([]byte{'\n'})
+		}
+	}
+
+	return s, nil
+}
+
+// Append 追加写入文件
+func (s *FileMessageStore) Append(record *ChatRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// 写入失败 id 也不复用，客户端不会收到重复的 id
+	s.lastId++
+	record.Id = s.lastId
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(b, '\n'))
+	if err != nil {
+		return err
+	}
+	s.remember(record)
+
+	return nil
+}
+
+// 更新最大 id 和房间最近的记录
+func (s *FileMessageStore) remember(record *ChatRecord) {
+	if record.Id > s.lastId {
+		s.lastId = record.Id
+	}
+	if s.keep <= 0 {
+		return
+	}
+	recent := append(s.recent[record.Room], record)
+	// 超出一倍时再截断，避免每次都复制
+	if len(recent) > 2*s.keep {
+		recent = append([]*ChatRecord{}, recent[len(recent)-s.keep:]...)
+	}
+	s.recent[record.Room] = recent
+}
+
+// Recent 房间最近的 n 条记录
+func (s *FileMessageStore) Recent(room string, n int) []*ChatRecord {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	recent := s.recent[room]
+	if n > s.keep {
+		n = s.keep
+	}
+	if n < len(recent) {
+		recent = recent[len(recent)-n:]
+	}
+	return append([]*ChatRecord{}, recent...)
+}
+
+// Query 顺序扫描文件，只保留最新的 Limit 条符合条件的记录；按从旧到新查询时找够 Limit 条就停止
+func (s *FileMessageStore) Query(q *MessageQuery) ([]*ChatRecord, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	matched := []*ChatRecord{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		record := &ChatRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			continue
+		}
+		if !q.Match(record) {
+			continue
+		}
+		matched = append(matched, record)
+		if q.Oldest && q.Limit > 0 && len(matched) == q.Limit {
+			return matched, nil
+		}
+		if q.Limit > 0 && len(matched) > 2*q.Limit {
+			matched = append([]*ChatRecord{}, matched[len(matched)-q.Limit:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if q.Oldest {
+		return matched, nil
+	}
+
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[len(matched)-q.Limit:]
+	}
+	// 从新到旧
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return matched, nil
+}
+
+// Close 关闭文件
+func (s *FileMessageStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.file.Close()
+}
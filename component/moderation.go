@@ -0,0 +1,214 @@
+package component
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Sanction 一条禁言或者封禁
+type Sanction struct {
+	Until  time.Time `json:"until"` // 到期时间，零值表示永久
+	Reason string    `json:"reason"`
+	By     string    `json:"by"`
+	Time   time.Time `json:"time"`
+}
+
+// 是否已经过期
+func (s *Sanction) expired(now time.Time) bool {
+	return !s.Until.IsZero() && now.After(s.Until)
+}
+
+// AuditRecord 一条管理操作记录
+type AuditRecord struct {
+	Time   time.Time  `json:"time"`
+	Actor  string     `json:"actor"`
+	Action string     `json:"action"`
+	Target string     `json:"target"`
+	Until  *time.Time `json:"until,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// BotTarget 按 bot id 处理
+func BotTarget(botId string) string {
+	return "bot:" + botId
+}
+
+// IpTarget 按 ip 处理
+func IpTarget(ip string) string {
+	return "ip:" + ip
+}
+
+// Moderation 禁言、封禁名单，每次修改后保存到 json 文件，重启后恢复
+// 所有管理操作追加写入审计日志，每行一条 json
+type Moderation struct {
+	lock  sync.RWMutex
+	path  string
+	audit *os.File
+	Mutes map[string]*Sanction `json:"mutes"` // bot:id 或者 ip:地址 => 禁言
+	Bans  map[string]*Sanction `json:"bans"`  // bot:id 或者 ip:地址 => 封禁
+}
+
+// NewModeration 只保存在内存中的名单
+func NewModeration() *Moderation {
+	return &Moderation{
+		Mutes: map[string]*Sanction{},
+		Bans:  map[string]*Sanction{},
+	}
+}
+
+// OpenModeration 读取名单，打开审计日志，路径为空时不保存
+func OpenModeration(path string, auditPath string) (*Moderation, error) {
+	m := NewModeration()
+	m.path = path
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err == nil {
+			if err := json.Unmarshal(b, m); err != nil {
+				return nil, err
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		if m.Mutes == nil {
+			m.Mutes = map[string]*Sanction{}
+		}
+		if m.Bans == nil {
+			m.Bans = map[string]*Sanction{}
+		}
+	}
+	if auditPath != "" {
+		if err := os.MkdirAll(filepath.Dir(auditPath), 0755); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(auditPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		m.audit = file
+	}
+
+	return m, nil
+}
+
+// Mute 禁言到 until，零值表示永久
+func (m *Moderation) Mute(target string, until time.Time, by string, reason string) error {
+	return m.set(m.Mutes, "mute", target, until, by, reason)
+}
+
+// Unmute 解除禁言
+func (m *Moderation) Unmute(target string, by string) error {
+	return m.unset(m.Mutes, "unmute", target, by)
+}
+
+// Ban 封禁到 until，零值表示永久
+func (m *Moderation) Ban(target string, until time.Time, by string, reason string) error {
+	return m.set(m.Bans, "ban", target, until, by, reason)
+}
+
+// Unban 解除封禁
+func (m *Moderation) Unban(target string, by string) error {
+	return m.unset(m.Bans, "unban", target, by)
+}
+
+// Muted 任意一个目标被禁言
+func (m *Moderation) Muted(targets ...string) (*Sanction, bool) {
+	return m.find(m.Mutes, targets)
+}
+
+// Banned 任意一个目标被封禁
+func (m *Moderation) Banned(targets ...string) (*Sanction, bool) {
+	return m.find(m.Bans, targets)
+}
+
+// Audit 记录管理操作
+func (m *Moderation) Audit(actor string, action string, target string, until time.Time, reason string) {
+	log.Printf("audit %v %v %v %v", actor, action, target, reason)
+	if m.audit == nil {
+		return
+	}
+	record := &AuditRecord{
+		Time:   time.Now(),
+		Actor:  actor,
+		Action: action,
+		Target: target,
+		Reason: reason,
+	}
+	if !until.IsZero() {
+		record.Until = &until
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("audit marshal err %v", err)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, err := m.audit.Write(append(b, '\n')); err != nil {
+		log.Printf("audit write err %v", err)
+	}
+}
+
+func (m *Moderation) set(list map[string]*Sanction, action string, target string, until time.Time, by string, reason string) error {
+	m.lock.Lock()
+	list[target] = &Sanction{
+		Until:  until,
+		Reason: reason,
+		By:     by,
+		Time:   time.Now(),
+	}
+	err := m.save()
+	m.lock.Unlock()
+
+	m.Audit(by, action, target, until, reason)
+	return err
+}
+
+func (m *Moderation) unset(list map[string]*Sanction, action string, target string, by string) error {
+	m.lock.Lock()
+	delete(list, target)
+	err := m.save()
+	m.lock.Unlock()
+
+	m.Audit(by, action, target, time.Time{}, "")
+	return err
+}
+
+func (m *Moderation) find(list map[string]*Sanction, targets []string) (*Sanction, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := time.Now()
+	for _, target := range targets {
+		if s, ok := list[target]; ok && !s.expired(now) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// 保存名单，顺便清理过期的
+func (m *Moderation) save() error {
+	if m.path == "" {
+		return nil
+	}
+	now := time.Now()
+	for _, list := range []map[string]*Sanction{m.Mutes, m.Bans} {
+		for target, s := range list {
+			if s.expired(now) {
+				delete(list, target)
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(m.path, b)
+}
@@ -0,0 +1,275 @@
+package component
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 默认的形近字表和拼音表
+const (
+	homoglyphsFile = "config/homoglyphs.txt"
+	pinyinFile     = "config/pinyin.txt"
+)
+
+// Normalizer 匹配前的文本归一化，对付用全角、大小写、插入空格和符号、繁体、形近字绕过敏感词
+// 每个字符依次做：全角转半角、转小写、形近字替换、繁体转简体，空白、标点、符号和不可见字符去掉
+// 开启拼音后，拼音表中有的汉字再转成拼音
+type Normalizer struct {
+	homoglyphs map[rune]rune
+	pinyin     map[rune]string // 没有开启拼音时为 nil
+}
+
+// LoadNormalizer 读取形近字表和拼音表，路径为空的表不使用
+func LoadNormalizer(homoglyphsPath, pinyinPath string) (*Normalizer, error) {
+	n := &Normalizer{homoglyphs: map[rune]rune{}}
+	if homoglyphsPath != "" {
+		// 每行一组，形近字在前，替换成的字在后，空格分隔
+		err := readTable(homoglyphsPath, func(from, to string) error {
+			f, fs := utf8.DecodeRuneInString(from)
+			t, ts := utf8.DecodeRuneInString(to)
+			if fs != len(from) || ts != len(to) {
+				return fmt.Errorf("homoglyph %q %q is not a single character", from, to)
+			}
+			n.homoglyphs[f] = t
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pinyinPath != "" {
+		// 每行一个拼音，后面是这个读音的汉字，多音字只取一个读音
+		n.pinyin = map[rune]string{}
+		err := readTable(pinyinPath, func(py, chars string) error {
+			for _, r := range chars {
+				n.pinyin[r] = py
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// 读取两列的表，空行和 # 开头的行跳过
+func readTable(path string, add func(a, b string) error) error {
+	fi, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = fi.Close()
+	}()
+
+	br := bufio.NewReader(fi)
+	for line := 1; ; line++ {
+		a, _, c := br.ReadLine()
+		if c == io.EOF {
+			break
+		}
+		l := strings.TrimSpace(string(a))
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		fields := strings.Fields(l)
+		if len(fields) != 2 {
+			return fmt.Errorf("%s:%d: want 2 fields, got %d", path, line, len(fields))
+		}
+		if err := add(fields[0], fields[1]); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+	}
+	return nil
+}
+
+// 单个字符归一化，去掉的字符返回 false
+func (n *Normalizer) fold(r rune) (rune, bool) {
+	// 全角字符和全角空格
+	if r >= 0xFF01 && r <= 0xFF5E {
+		r -= 0xFEE0
+	} else if r == 0x3000 {
+		r = ' '
+	}
+	r = unicode.ToLower(r)
+	// 形近字在去掉符号之前替换，带圈字母、康熙部首这些本身是符号
+	if h, ok := n.homoglyphs[r]; ok {
+		r = h
+	}
+	if s, ok := t2s[r]; ok {
+		r = s
+	}
+	if separator(r) {
+		return 0, false
+	}
+	return r, true
+}
+
+// 空白、标点、符号、控制字符、零宽字符和组合附加符号
+func separator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsControl(r) ||
+		unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Mn, r)
+}
+
+// Normalize 归一化后的文本，pinyin 为 true 时汉字转成拼音
+func (n *Normalizer) Normalize(text string, pinyin bool) string {
+	var b strings.Builder
+	for _, r := range text {
+		n.write(&b, r, pinyin)
+	}
+	return b.String()
+}
+
+// 写入字符 r 归一化的结果
+func (n *Normalizer) write(b *strings.Builder, r rune, pinyin bool) {
+	r, ok := n.fold(r)
+	if !ok {
+		return
+	}
+	if pinyin {
+		if py, ok := n.pinyin[r]; ok {
+			b.WriteString(py)
+			return
+		}
+	}
+	b.WriteRune(r)
+}
+
+// HasPinyin 是否开启了拼音
+func (n *Normalizer) HasPinyin() bool {
+	return n.pinyin != nil
+}
+
+// 汉字个数，word 中有不在拼音表中的汉字时返回 -1
+func (n *Normalizer) pinyinHans(word string) int {
+	hans := 0
+	for _, r := range word {
+		if !unicode.Is(unicode.Han, r) {
+			continue
+		}
+		if _, ok := n.pinyin[r]; !ok {
+			return -1
+		}
+		hans++
+	}
+	return hans
+}
+
+// 原文中有没有会转成拼音的汉字
+func (n *Normalizer) hasPinyinHan(text string) bool {
+	for _, r := range text {
+		r, ok := n.fold(r)
+		if !ok {
+			continue
+		}
+		if _, ok := n.pinyin[r]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// 归一化后的文本，spans 记录每个字节来自原文的哪个字符，用来把命中的位置换算回原文
+type normalized struct {
+	text  string
+	spans []span
+}
+
+type span struct {
+	start int
+	end   int
+}
+
+func (n *Normalizer) normalizeSpans(text string, pinyin bool) *normalized {
+	var b strings.Builder
+	spans := make([]span, 0, len(text))
+	for i, r := range text {
+		// 非法的 utf8 字节按一个字符处理
+		size := utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			_, size = utf8.DecodeRuneInString(text[i:])
+		}
+		before := b.Len()
+		n.write(&b, r, pinyin)
+		for j := before; j < b.Len(); j++ {
+			spans = append(spans, span{i, i + size})
+		}
+	}
+	return &normalized{text: b.String(), spans: spans}
+}
+
+// 把归一化文本中的命中换算成原文中的范围，中间去掉的空格、符号一起算在命中里
+func (t *normalized) origin(m Match) Match {
+	return Match{
+		Start: t.spans[m.Start].start,
+		End:   t.spans[m.End-1].end,
+		Word:  m.Word,
+	}
+}
+
+// 不超过这个长度的纯字母数字敏感词，命中时前后必须是词的边界
+// 去掉空格、符号后，正常的英文里到处都是 sm、ly、qq 这样的几个字母
+const asciiWordMaxLen = 3
+
+// 是否是需要词边界的短敏感词，word 是归一化后的
+func shortASCII(word string) bool {
+	if len(word) > asciiWordMaxLen {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		if asciiClass(rune(word[i])) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 字母、数字各算一类，其他字符为 0
+func asciiClass(r rune) int {
+	switch {
+	case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		return 1
+	case r >= '0' && r <= '9':
+		return 2
+	}
+	return 0
+}
+
+// 原文中命中的 m 前后是否是词的边界：前一个字符和 word 的第一个字符、后一个字符和 word 的最后一个字符不是同一类
+// 比如 "abs music" 中的 "s m"、"really" 中的 "ly" 都不是，"我的QQ号"、"QQ123" 中的 "QQ" 是
+func (n *Normalizer) bounded(text string, m Match, word string) bool {
+	if m.Start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(text[:m.Start])
+		if n.sameClass(r, rune(word[0])) {
+			return false
+		}
+	}
+	if m.End < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[m.End:])
+		if n.sameClass(r, rune(word[len(word)-1])) {
+			return false
+		}
+	}
+	return true
+}
+
+// 原文字符 r 归一化后和 w 是不是同一类，全角、形近的字母也算字母
+func (n *Normalizer) sameClass(r, w rune) bool {
+	r, ok := n.fold(r)
+	return ok && asciiClass(r) == asciiClass(w)
+}
+
+// 命中的开头和结尾是否都在原文字符的边界上，一个汉字转成的拼音不能只命中一部分
+func (t *normalized) aligned(m Match) bool {
+	if m.Start > 0 && t.spans[m.Start-1] == t.spans[m.Start] {
+		return false
+	}
+	return m.End == len(t.spans) || t.spans[m.End] != t.spans[m.End-1]
+}
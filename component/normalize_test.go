@@ -0,0 +1,104 @@
+package component
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	n := &Normalizer{homoglyphs: map[rune]rune{'а': 'a', 'ⓑ': 'b', '0': 'o'}}
+	for _, c := range []struct{ text, want string }{
+		// 全角转半角，大写转小写
+		{"ＱＱ", "qq"},
+		{"Ａ　Ｂ", "ab"},
+		{"AbC", "abc"},
+		// 空白、标点、符号、零宽字符、组合附加符号去掉
+		{"s.m", "sm"},
+		{"S-M!", "sm"},
+		{"s\u200bm", "sm"},
+		{"é", "e"},
+		{"我 的，号", "我的号"},
+		// 形近字，带圈字母本身是符号，要在去掉符号之前替换
+		{"bаd", "bad"},
+		{"ⓑad", "bad"},
+		{"g00d", "good"},
+		// 繁体转简体
+		{"臺灣", "台湾"},
+	} {
+		if got := n.Normalize(c.text, false); got != c.want {
+			t.Errorf("Normalize(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestNormalizePinyin(t *testing.T) {
+	n := &Normalizer{pinyin: map[rune]string{'法': "fa", '轮': "lun", '功': "gong"}}
+	for _, c := range []struct {
+		text   string
+		pinyin bool
+		want   string
+	}{
+		{"法轮功", false, "法轮功"},
+		{"法轮功", true, "falungong"},
+		{"法 輪-功", true, "falungong"},
+		{"法lun功", true, "falungong"},
+		// 拼音表里没有的字不转
+		{"我的法", true, "我的fa"},
+	} {
+		if got := n.Normalize(c.text, c.pinyin); got != c.want {
+			t.Errorf("Normalize(%q, %v) = %q, want %q", c.text, c.pinyin, got, c.want)
+		}
+	}
+	for _, c := range []struct {
+		text string
+		want bool
+	}{
+		{"法lun", true},
+		{"fa輪", true},
+		{"falun", false},
+		{"我的", false},
+	} {
+		if got := n.hasPinyinHan(c.text); got != c.want {
+			t.Errorf("hasPinyinHan(%q) = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
+
+// 归一化文本中的命中换算回原文的范围
+func TestNormalizeSpans(t *testing.T) {
+	n := &Normalizer{pinyin: map[rune]string{'法': "fa"}}
+	cases := []struct {
+		text    string
+		pinyin  bool
+		norm    string
+		match   Match
+		aligned bool
+		want    Match
+	}{
+		// 全角字符占 3 个字节，中间去掉的空格算在命中里
+		{"Ａ b法", false, "ab法", Match{Start: 0, End: 2}, true, Match{Start: 0, End: 5}},
+		{"Ａ b法", false, "ab法", Match{Start: 2, End: 5}, true, Match{Start: 5, End: 8}},
+		// 一个汉字的拼音对应原文的整个字
+		{"Ａ b法", true, "abfa", Match{Start: 2, End: 4}, true, Match{Start: 5, End: 8}},
+		{"Ａ b法", true, "abfa", Match{Start: 1, End: 4}, true, Match{Start: 4, End: 8}},
+		// 只命中拼音的一部分
+		{"Ａ b法", true, "abfa", Match{Start: 2, End: 3}, false, Match{Start: 5, End: 8}},
+		{"Ａ b法", true, "abfa", Match{Start: 1, End: 3}, false, Match{Start: 4, End: 8}},
+		// 非法的 utf8 字节按一个字符去掉
+		{"a\xffb", false, "ab", Match{Start: 0, End: 2}, true, Match{Start: 0, End: 3}},
+	}
+	for _, c := range cases {
+		got := n.normalizeSpans(c.text, c.pinyin)
+		if got.text != c.norm {
+			t.Errorf("normalizeSpans(%q, %v) = %q, want %q", c.text, c.pinyin, got.text, c.norm)
+			continue
+		}
+		if len(got.spans) != len(got.text) {
+			t.Errorf("normalizeSpans(%q, %v) has %d spans for %d bytes", c.text, c.pinyin, len(got.spans), len(got.text))
+			continue
+		}
+		if a := got.aligned(c.match); a != c.aligned {
+			t.Errorf("%q aligned(%+v) = %v, want %v", c.norm, c.match, a, c.aligned)
+		}
+		if o := got.origin(c.match); o != c.want {
+			t.Errorf("%q origin(%+v) = %+v, want %+v", c.norm, c.match, o, c.want)
+		}
+	}
+}
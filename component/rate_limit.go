@@ -0,0 +1,89 @@
+package component
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶，每秒补充 rate 个令牌，最多存 burst 个，每条消息消耗一个
+// 速率由调用方传入，配置重新加载后立即生效；零值是满的桶
+type TokenBucket struct {
+	used float64 // 已经消耗的令牌数，为 0 表示桶是满的
+	last time.Time
+}
+
+// Allow 取一个令牌，没有令牌时返回 false，rate 小于等于 0 表示不限制
+func (b *TokenBucket) Allow(rate float64, burst int, now time.Time) bool {
+	if rate <= 0 {
+		return true
+	}
+	b.refill(rate, now)
+	if b.used+1 > float64(burst) {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// Reset 补满
+func (b *TokenBucket) Reset() {
+	b.used = 0
+}
+
+func (b *TokenBucket) refill(rate float64, now time.Time) {
+	if !b.last.IsZero() {
+		b.used -= now.Sub(b.last).Seconds() * rate
+		if b.used < 0 {
+			b.used = 0
+		}
+	}
+	b.last = now
+}
+
+// 清理的间隔
+const rateLimiterPruneInterval = time.Minute
+
+// RateLimiter 按 key 区分的令牌桶，比如按 ip 限流，可以在多个协程中使用
+type RateLimiter struct {
+	lock    sync.Mutex
+	buckets map[string]*TokenBucket
+	pruned  time.Time
+}
+
+// NewRateLimiter ...
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: map[string]*TokenBucket{},
+	}
+}
+
+// Allow 取 key 对应的桶中的一个令牌
+func (l *RateLimiter) Allow(key string, rate float64, burst int, now time.Time) bool {
+	if rate <= 0 {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.prune(rate, now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &TokenBucket{}
+		l.buckets[key] = b
+	}
+	return b.Allow(rate, burst, now)
+}
+
+// 定期删除已经补满的桶，补满的桶和新建的一样
+func (l *RateLimiter) prune(rate float64, now time.Time) {
+	if now.Sub(l.pruned) < rateLimiterPruneInterval {
+		return
+	}
+	l.pruned = now
+	for key, b := range l.buckets {
+		b.refill(rate, now)
+		if b.used == 0 {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package component
+
+// 繁体转简体，覆盖默认词库中用到的字和一些常用字，每一对是繁体在前、简体在后
+const t2sPairs = "萬万 與与 專专 絲丝 東东 個个 麗丽 舉举 麼么 麽么 義义 樂乐 習习 書书 買买 亂乱 亞亚 產产 産产 親亲 " +
+	"褻亵 侖仑 倉仓 從从 儀仪 價价 優优 會会 傳传 傷伤 倫伦 體体 侶侣 偵侦 黨党 蘭兰 興兴 獸兽 內内 冊册 " +
+	"寫写 軍军 農农 準准 鳳凤 擊击 劉刘 則则 剛刚 劑剂 劍剑 劒剑 辦办 動动 勳勋 勛勋 華华 賣卖 衛卫 捲卷 " +
+	"歷历 曆历 壓压 廁厕 雙双 發发 髮发 疊叠 葉叶 後后 嚮向 呂吕 吳吴 週周 噴喷 團团 糰团 園园 國国 圖图 " +
+	"壇坛 罈坛 處处 備备 復复 複复 頭头 婦妇 媽妈 嬈娆 嬰婴 學学 孫孙 寧宁 寶宝 憲宪 賓宾 導导 爾尔 屬属 " +
+	"嵐岚 島岛 峯峰 師师 幫帮 廣广 莊庄 慶庆 應应 開开 彈弹 強强 當当 噹当 徑径 戰战 戲戏 戶户 擋挡 擇择 " +
+	"換换 攝摄 時时 曉晓 術术 樸朴 機机 殺杀 權权 楊杨 傑杰 極极 槍枪 楓枫 標标 棟栋 樹树 樣样 檔档 樓楼 " +
+	"歡欢 歐欧 殘残 氣气 氫氢 湯汤 溝沟 滬沪 澤泽 潔洁 濤涛 滿满 靈灵 煉炼 爛烂 燒烧 熱热 愛爱 獵猎 豬猪 " +
+	"瑪玛 環环 現现 電电 畫画 監监 盜盗 盤盘 禮礼 種种 祕秘 禿秃 簡简 類类 級级 純纯 紙纸 線线 組组 紹绍 " +
+	"經经 絡络 綿绵 縫缝 網网 羅罗 職职 聯联 腎肾 勝胜 鬍胡 膠胶 腦脑 腳脚 脫脱 騰腾 艷艳 豔艳 藝艺 蘇苏 " +
+	"甦苏 莖茎 蕩荡 榮荣 蔭荫 藥药 營营 蟲虫 蟻蚁 襪袜 裝装 襠裆 褲裤 見见 視视 計计 訂订 讓让 訊讯 記记 " +
+	"許许 論论 證证 試试 話话 詳详 語语 誘诱 說说 説说 請请 調调 讜谠 貢贡 賢贤 貨货 質质 貪贪 購购 賤贱 " +
+	"貴贵 貸贷 費费 賀贺 賊贼 賈贾 資资 趙赵 躍跃 車车 轉转 輪轮 輕轻 載载 達达 過过 運运 進进 遠远 連连 " +
+	"鄧邓 鄭郑 釋释 裡里 裏里 針针 鐘钟 鍾钟 鋼钢 錢钱 鉀钾 鐵铁 鈴铃 鉛铅 銬铐 銘铭 鏟铲 銨铵 銷销 鎖锁 " +
+	"鋒锋 銳锐 錫锡 錦锦 鎮镇 鎔镕 鏢镖 長长 門门 閩闽 陽阳 陰阴 際际 陸陆 陳陈 隱隐 順顺 領领 頻频 顏颜 " +
+	"額额 風风 飆飚 飈飚 飢饥 饑饥 飽饱 馬马 駑驽 驗验 騎骑 騷骚 鳥鸟 雞鸡 鷄鸡 鳴鸣 鴻鸿 鵬鹏 鶴鹤 鷹鹰 " +
+	"黃黄 龍龙 龜龟 兒儿 幾几 劃划 製制 區区 聲声 張张 總总 惡恶 愷恺 驚惊 慘惨 無无 來来 點点 紅红 緊紧 " +
+	"翹翘 癢痒 貓猫 誌志 幹干 瀋沈 瞭了 雲云 臺台 檯台 颱台 灣湾 這这 們们 對对 為为 爲为 沒没 還还 讀读 " +
+	"鬥斗 獨独 聽听 認认 議议 將将 辭辞 顯显 關关 問问 題题 務务 歲岁 條条 舊旧 實实 嗎吗 錯错 謝谢 邊边 " +
+	"號号 臉脸 錄录 憶忆 擁拥 夾夹 夢梦 檢检 執执 塊块 壞坏"
+
+var t2s = pairTable(t2sPairs)
+
+// 解析 "繁简 繁简" 这样两个字一组的对照表
+func pairTable(pairs string) map[rune]rune {
+	table := map[rune]rune{}
+	var from rune
+	n := 0
+	for _, r := range pairs {
+		if r == ' ' {
+			n = 0
+			continue
+		}
+		if n == 0 {
+			from = r
+		} else {
+			table[from] = r
+		}
+		n++
+	}
+	return table
+}
@@ -0,0 +1,37 @@
+package component
+
+import (
+	"strings"
+	"testing"
+)
+
+// 对照表中每一组都是两个字，并且按写的方向映射
+func TestT2sPairs(t *testing.T) {
+	pairs := strings.Fields(t2sPairs)
+	for _, p := range pairs {
+		r := []rune(p)
+		if len(r) != 2 {
+			t.Errorf("pair %q has %d characters, want 2", p, len(r))
+			continue
+		}
+		if got := t2s[r[0]]; got != r[1] {
+			t.Errorf("t2s[%q] = %q, want %q", r[0], got, r[1])
+		}
+	}
+	if len(t2s) != len(pairs) {
+		t.Errorf("t2s has %d entries, want %d", len(t2s), len(pairs))
+	}
+}
+
+func TestNormalizeT2s(t *testing.T) {
+	n := &Normalizer{}
+	for _, c := range []struct{ text, want string }{
+		{"灣", "湾"},
+		{"颱", "台"},
+		{"臺灣", "台湾"},
+	} {
+		if got := n.Normalize(c.text, false); got != c.want {
+			t.Errorf("Normalize(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
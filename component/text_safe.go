@@ -2,21 +2,199 @@ package component
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
-
-	filter "github.com/antlinker/go-dirtyfilter"
-	"github.com/antlinker/go-dirtyfilter/store"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// 默认的词库文件
+const wordsFilterFile = "config/words_filter.txt"
+
+// 至少有几个汉字的敏感词才按拼音匹配，单个字的拼音太容易误伤
+const pinyinMinHans = 2
+
+// TextSafe 敏感词过滤
+// 词库文件修改后自动重新加载，也可以在运行中增删敏感词并保存到文件
+// 重新加载时先建好新的过滤器再整体替换，替换完成之前一直使用旧的过滤器
+// 文本和敏感词先经过 Normalizer 归一化再匹配，替换的是原文中对应的部分
 type TextSafe struct {
-	filter *filter.DirtyManager
+	Path           string       // 词库文件，为空时使用 config/words_filter.txt
+	HomoglyphsPath string       // 形近字表，为空时使用 config/homoglyphs.txt
+	Pinyin         bool         // 是否按拼音匹配
+	PinyinPath     string       // 拼音表，为空时使用 config/pinyin.txt
+	lock           sync.Mutex   // 重新加载和增删敏感词互斥
+	current        atomic.Value // 当前的过滤器 *textFilter
+	stamp          string       // 最近一次加载时各个文件的修改时间和大小
+}
+
+// 一个版本的词库和匹配器，建好之后不再修改
+type textFilter struct {
+	words        []string
+	norm         *Normalizer
+	matcher      Matcher           // 归一化后的敏感词
+	origin       map[string]string // 归一化后的敏感词对应词库中的词
+	pinyin       Matcher           // 敏感词的拼音，没有开启拼音时为 nil
+	pinyinOrigin map[string]string
+	raw          Matcher // 归一化后什么都不剩的敏感词，比如全是符号的，直接匹配原文
+}
+
+func newTextFilter(words []string, norm *Normalizer) *textFilter {
+	f := &textFilter{
+		words:        words,
+		norm:         norm,
+		origin:       map[string]string{},
+		pinyinOrigin: map[string]string{},
+	}
+	var normWords, pinyinWords, rawWords []string
+	for _, w := range words {
+		nw := norm.Normalize(w, false)
+		if nw == "" {
+			rawWords = append(rawWords, w)
+			continue
+		}
+		if _, ok := f.origin[nw]; !ok {
+			f.origin[nw] = w
+			normWords = append(normWords, nw)
+		}
+		if !norm.HasPinyin() || norm.pinyinHans(nw) < pinyinMinHans {
+			continue
+		}
+		pw := norm.Normalize(nw, true)
+		if _, ok := f.pinyinOrigin[pw]; !ok {
+			f.pinyinOrigin[pw] = w
+			pinyinWords = append(pinyinWords, pw)
+		}
+	}
+	f.matcher = NewAhoCorasick(normWords)
+	f.raw = NewAhoCorasick(rawWords)
+	if norm.HasPinyin() {
+		f.pinyin = NewAhoCorasick(pinyinWords)
+	}
+	return f
+}
+
+// 匹配原文和归一化后的文本，命中的位置都是原文中的
+func (f *textFilter) find(text string) []Match {
+	matches := f.raw.FindAll(text)
+	matches = append(matches, f.findNormalized(text, false)...)
+	if f.pinyin != nil {
+		matches = append(matches, f.findNormalized(text, true)...)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End < b.End
+		}
+		return a.Word < b.Word
+	})
+	// 原文和拼音都命中同一处时只保留一个
+	uniq := matches[:0]
+	for i, m := range matches {
+		if i > 0 && m == matches[i-1] {
+			continue
+		}
+		uniq = append(uniq, m)
+	}
+	return uniq
+}
+
+func (f *textFilter) findNormalized(text string, pinyin bool) []Match {
+	matcher, origin := f.matcher, f.origin
+	if pinyin {
+		matcher, origin = f.pinyin, f.pinyinOrigin
+	}
+	t := f.norm.normalizeSpans(text, pinyin)
+	var matches []Match
+	for _, m := range matcher.FindAll(t.text) {
+		// 拼音要从一个字的开头匹配到一个字的结尾
+		if !t.aligned(m) {
+			continue
+		}
+		word := m.Word
+		m = t.origin(m)
+		// 短的字母数字敏感词只按整个词匹配
+		// 拼音命中的原文里没有汉字时也一样，正常的英文里到处都是拼音，比如 "mechanism" 中的 "chani"
+		spelled := pinyin && !f.norm.hasPinyinHan(text[m.Start:m.End])
+		if (shortASCII(word) || spelled) && !f.norm.bounded(text, m, word) {
+			continue
+		}
+		m.Word = origin[word]
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+func (s *TextSafe) path() string {
+	if s.Path == "" {
+		return wordsFilterFile
+	}
+	return s.Path
+}
+
+func (s *TextSafe) homoglyphsPath() string {
+	if s.HomoglyphsPath == "" {
+		return homoglyphsFile
+	}
+	return s.HomoglyphsPath
+}
+
+func (s *TextSafe) pinyinPath() string {
+	if !s.Pinyin {
+		return ""
+	}
+	if s.PinyinPath == "" {
+		return pinyinFile
+	}
+	return s.PinyinPath
+}
+
+// 加载时用到的所有文件的修改时间和大小，有一个变了就需要重新加载
+func (s *TextSafe) fileStamp() (string, error) {
+	var b strings.Builder
+	for _, path := range []string{s.path(), s.homoglyphsPath(), s.pinyinPath()} {
+		if path == "" {
+			continue
+		}
+		stat, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", path, stat.ModTime().UnixNano(), stat.Size())
+	}
+	return b.String(), nil
 }
 
 func (s *TextSafe) NewFilter() error {
-	fi, err := os.Open("config/words_filter.txt")
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.load()
+}
+
+// 读取词库文件、形近字表和拼音表，建好过滤器后替换
+func (s *TextSafe) load() error {
+	// 先取修改时间再读，读的过程中文件又被修改的话下次检查时会再加载一次
+	stamp, err := s.fileStamp()
+	if err != nil {
+		log.Printf("stat words_filter err %v", err)
+		return err
+	}
+
+	norm, err := LoadNormalizer(s.homoglyphsPath(), s.pinyinPath())
+	if err != nil {
+		log.Printf("load normalizer err %v", err)
+		return err
+	}
+
+	fi, err := os.Open(s.path())
 	if err != nil {
 		log.Printf("open words_filter err %v", err)
 		return err
@@ -33,34 +211,115 @@ func (s *TextSafe) NewFilter() error {
 		if c == io.EOF {
 			break
 		}
-		words = append(words, string(a))
+		if w := strings.TrimSpace(string(a)); w != "" {
+			words = append(words, w)
+		}
 	}
 
-	memStore, err := store.NewMemoryStore(store.MemoryConfig{
-		DataSource: words,
-	})
+	s.current.Store(newTextFilter(words, norm))
+	s.stamp = stamp
+	return nil
+}
 
-	if err != nil {
-		log.Printf("NewMemoryStore err %v", err)
-		return err
+// Watch 每隔 interval 检查词库文件、形近字表和拼音表，修改过就重新加载，加载失败时保留原来的词库
+func (s *TextSafe) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		stamp, err := s.fileStamp()
+		if err != nil {
+			log.Printf("stat words_filter err %v", err)
+			continue
+		}
+		s.lock.Lock()
+		if stamp != s.stamp {
+			if err := s.load(); err != nil {
+				log.Printf("reload words_filter err %v", err)
+			} else {
+				log.Printf("words_filter reloaded, %d words", len(s.Words()))
+			}
+		}
+		s.lock.Unlock()
 	}
+}
 
-	s.filter = filter.NewDirtyManager(memStore)
-	return nil
+// Words 当前的敏感词
+func (s *TextSafe) Words() []string {
+	f, ok := s.current.Load().(*textFilter)
+	if !ok {
+		return nil
+	}
+	return f.words
 }
 
-func (s *TextSafe) Filter(filterText string) string {
-	result, err := s.filter.Filter().Filter(filterText, '*', '@')
-	if err != nil {
-		log.Print(err)
-		return ""
+// AddWords 添加敏感词，保存到词库文件后生效，返回新增的个数
+func (s *TextSafe) AddWords(words ...string) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	exists := map[string]bool{}
+	next := []string{}
+	for _, w := range s.Words() {
+		exists[w] = true
+		next = append(next, w)
 	}
+	n := 0
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w == "" || exists[w] {
+			continue
+		}
+		exists[w] = true
+		next = append(next, w)
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, s.replace(next)
+}
 
-	if result != nil {
-		for _, w := range result {
-			filterText = strings.ReplaceAll(filterText, w, "*")
+// RemoveWords 删除敏感词，保存到词库文件后生效，返回删除的个数
+func (s *TextSafe) RemoveWords(words ...string) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	remove := map[string]bool{}
+	for _, w := range words {
+		remove[strings.TrimSpace(w)] = true
+	}
+	next := []string{}
+	for _, w := range s.Words() {
+		if !remove[w] {
+			next = append(next, w)
 		}
 	}
+	n := len(s.Words()) - len(next)
+	if n == 0 {
+		return 0, nil
+	}
+	return n, s.replace(next)
+}
+
+// 写入词库文件后重新加载，形近字表、拼音表也一起重新读取
+func (s *TextSafe) replace(words []string) error {
+	if err := writeFileAtomic(s.path(), []byte(strings.Join(words, "\n")+"\n")); err != nil {
+		return err
+	}
+	return s.load()
+}
 
-	return filterText
+// Find 文本中命中的敏感词
+func (s *TextSafe) Find(text string) []Match {
+	f, ok := s.current.Load().(*textFilter)
+	if !ok {
+		return nil
+	}
+	return f.find(text)
+}
+
+// Filter 敏感词逐字替换成 *，替换后字数不变
+func (s *TextSafe) Filter(filterText string) string {
+	return Mask(filterText, s.Find(filterText), '*')
 }
@@ -0,0 +1,58 @@
+package component
+
+import "testing"
+
+// 短的字母数字敏感词只按整个词匹配，正常英文去掉空格后不能误伤
+func TestFilterShortASCII(t *testing.T) {
+	norm, err := LoadNormalizer("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newTextFilter([]string{"SM", "LY", "QQ", "3P", "fuck"}, norm)
+	for _, c := range []struct{ text, want string }{
+		{"this is my house", "this is my house"},
+		{"really nice", "really nice"},
+		{"abs music", "abs music"},
+		{"it's my cat", "it's my cat"},
+		{"13P", "13P"},
+		{"I like SM", "I like **"},
+		{"我的Ｑ-q号", "我的***号"},
+		{"加QQ123456", "加**123456"},
+		{"看S.M.片", "看***.片"},
+		{"3P", "**"},
+		{"fucking", "****ing"},
+	} {
+		if got := Mask(c.text, f.find(c.text), '*'); got != c.want {
+			t.Errorf("filter %q = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+// 全是字母写的拼音只按整个词匹配，正常英文里拼出来的拼音不能误伤
+func TestFilterPinyin(t *testing.T) {
+	norm := &Normalizer{pinyin: map[rune]string{
+		'插': "cha", '茶': "cha", '你': "ni", '泥': "ni", '色': "se", '区': "qu",
+		'大': "da", '波': "bo", '法': "fa", '轮': "lun", '功': "gong",
+	}}
+	f := newTextFilter([]string{"插你", "色区", "色色", "大波", "法轮功"}, norm)
+	for _, c := range []struct{ text, want string }{
+		{"mechanism", "mechanism"},
+		{"subsequent", "subsequent"},
+		{"ParseSetCookie", "ParseSetCookie"},
+		{"and boolean", "and boolean"},
+		{"woaifalungong", "woaifalungong"},
+		{"I like falungong!", "I like *********!"},
+		{"falun gong", "**********"},
+		{"FaLunGong123", "*********123"},
+		// 原文中有汉字的照常匹配
+		{"插你", "**"},
+		{"茶泥", "**"},
+		{"法lun功", "*****"},
+		{"我爱法lun功啊", "我爱*****啊"},
+		{"x茶ni", "x***"},
+	} {
+		if got := Mask(c.text, f.find(c.text), '*'); got != c.want {
+			t.Errorf("filter %q = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
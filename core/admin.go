@@ -0,0 +1,257 @@
+package core
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sunshinev/go-space-chat/component"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 管理接口在审计日志中的操作人
+const adminApiActor = "admin-api"
+
+// 管理接口只允许管理员 token 访问，token 放在 Authorization: Bearer 头或者 token 参数中
+func (s *Core) adminOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+		if role, ok := s.authenticate(token); !ok || role < RoleAdmin {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// 修改类的接口只接受 POST
+func postOnly(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+// 输出 json
+func writeJson(w http.ResponseWriter, api string, data interface{}) {
+	d, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("%v marsharl %v", api, err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	_, err = w.Write(d)
+	if err != nil {
+		log.Printf("%v write %v", api, err)
+	}
+}
+
+type AdminClientRsp struct {
+	BotId     string    `json:"bot_id"`
+	Name      string    `json:"name"`
+	Ip        string    `json:"ip"`
+	PosInfo   *pb.PInfo `json:"pos_info"`
+	Room      string    `json:"room"`
+	Role      string    `json:"role"`
+	Since     time.Time `json:"since"`     // 当前连接的建立时间
+	Chats     int64     `json:"chats"`     // 发送的聊天消息数
+	Statuses  int64     `json:"statuses"`  // 上报的状态数
+	Corrected int64     `json:"corrected"` // 移动太快被纠正的次数
+	Detached  bool      `json:"detached"`  // 断线等待重连
+	QueueSize int       `json:"queue"`     // 发送队列中的消息数
+}
+
+// AdminClientsApi 在线客户列表
+func (s *Core) AdminClientsApi(w http.ResponseWriter, r *http.Request) {
+	data := []AdminClientRsp{}
+	s.Clients.Range(func(_, v interface{}) bool {
+		c, ok := v.(*Client)
+		if !ok {
+			return true
+		}
+		s.roomLock.RLock()
+		room := s.clientRoom[c]
+		s.roomLock.RUnlock()
+
+		c.lock.Lock()
+		item := AdminClientRsp{
+			BotId:     c.Info.BotId,
+			Name:      c.Info.Name,
+			Ip:        c.ip,
+			PosInfo:   c.Info.PosInfo,
+			Role:      c.role.String(),
+			Since:     c.since,
+			Detached:  c.detached,
+			QueueSize: len(c.queue),
+		}
+		c.lock.Unlock()
+		item.Chats = atomic.LoadInt64(&c.chats)
+		item.Statuses = atomic.LoadInt64(&c.statuses)
+		item.Corrected = atomic.LoadInt64(&c.corrected)
+		if room != nil {
+			item.Room = room.Name
+		}
+		data = append(data, item)
+		return true
+	})
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Since.Before(data[j].Since)
+	})
+
+	writeJson(w, "AdminClientsApi", data)
+}
+
+type AdminKickRsp struct {
+	Kicked int `json:"kicked"`
+}
+
+// AdminKickApi 踢下线，参数 target 为 bot id 或者 ip
+func (s *Core) AdminKickApi(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r) {
+		return
+	}
+	arg := r.FormValue("target")
+	if arg == "" {
+		http.Error(w, "target required", http.StatusBadRequest)
+		return
+	}
+	target := parseTarget(arg)
+	data := &AdminKickRsp{
+		Kicked: s.kick(target, "kicked"),
+	}
+	s.Moderation.Audit(adminApiActor, "kick", target, time.Time{}, r.FormValue("reason"))
+
+	writeJson(w, "AdminKickApi", data)
+}
+
+type AdminAnnounceRsp struct {
+	Sent int `json:"sent"`
+}
+
+// AdminAnnounceApi 系统公告，发给所有在线客户，参数 msg
+func (s *Core) AdminAnnounceApi(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r) {
+		return
+	}
+	msg := strings.TrimSpace(r.FormValue("msg"))
+	if msg == "" {
+		http.Error(w, "msg required", http.StatusBadRequest)
+		return
+	}
+	data := &AdminAnnounceRsp{}
+	f := announceFrame(msg)
+	s.Clients.Range(func(_, v interface{}) bool {
+		if c, ok := v.(*Client); ok {
+			c.Send(f)
+			data.Sent++
+		}
+		return true
+	})
+	s.Moderation.Audit(adminApiActor, "announce", "", time.Time{}, msg)
+
+	writeJson(w, "AdminAnnounceApi", data)
+}
+
+// AdminReloadApi 重新加载配置文件，失败时保留原来的配置
+func (s *Core) AdminReloadApi(w http.ResponseWriter, r *http.Request) {
+	if !postOnly(w, r) {
+		return
+	}
+	if err := s.LoadConfig(); err != nil {
+		log.Printf("AdminReloadApi load config %v", err)
+		http.Error(w, "reload failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.Moderation.Audit(adminApiActor, "reload", "", time.Time{}, "")
+
+	conf := *s.Conf()
+	// 不返回 token
+	conf.AdminTokens, conf.ModeratorTokens = "", ""
+	writeJson(w, "AdminReloadApi", conf)
+}
+
+type AdminIpFilterRsp struct {
+	Allow  []string `json:"allow"`
+	Deny   []string `json:"deny"`
+	Kicked int      `json:"kicked,omitempty"` // 加入黑名单后踢掉的连接数
+}
+
+// AdminIpFilterApi ip 黑白名单，GET 查看；POST 修改，参数 action 为 add、remove，list 为 allow、deny，entry 为 ip 或者 CIDR 网段
+func (s *Core) AdminIpFilterApi(w http.ResponseWriter, r *http.Request) {
+	data := &AdminIpFilterRsp{}
+	if r.Method == http.MethodPost {
+		action, list, entry := r.FormValue("action"), r.FormValue("list"), strings.TrimSpace(r.FormValue("entry"))
+		var err error
+		switch action {
+		case "add":
+			err = s.IpFilter.Add(list, entry)
+		case "remove":
+			err = s.IpFilter.Remove(list, entry)
+		default:
+			http.Error(w, "unknown action", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("AdminIpFilterApi %v %v %v err %v", action, list, entry, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.Moderation.Audit(adminApiActor, "ip_"+list+"_"+action, entry, time.Time{}, "")
+		if action == "add" && list == component.IpDeny {
+			data.Kicked = s.kickDenied()
+		}
+	}
+	data.Allow, data.Deny = s.IpFilter.Lists()
+
+	writeJson(w, "AdminIpFilterApi", data)
+}
+
+type AdminWordsRsp struct {
+	Changed int      `json:"changed,omitempty"` // 新增或者删除的个数
+	Words   []string `json:"words"`
+}
+
+// AdminWordsApi 敏感词，GET 查看；POST 修改，参数 action 为 add、remove，word 可以有多个
+// 修改后保存到词库文件，立即生效
+func (s *Core) AdminWordsApi(w http.ResponseWriter, r *http.Request) {
+	data := &AdminWordsRsp{}
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		action, words := r.Form.Get("action"), r.Form["word"]
+		if len(words) == 0 {
+			http.Error(w, "word required", http.StatusBadRequest)
+			return
+		}
+		var err error
+		switch action {
+		case "add":
+			data.Changed, err = s.TextSafer.AddWords(words...)
+		case "remove":
+			data.Changed, err = s.TextSafer.RemoveWords(words...)
+		default:
+			http.Error(w, "unknown action", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("AdminWordsApi %v err %v", action, err)
+			http.Error(w, "save failed: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.Moderation.Audit(adminApiActor, "words_"+action, "", time.Time{}, strings.Join(words, ","))
+	}
+	data.Words = s.TextSafer.Words()
+
+	writeJson(w, "AdminWordsApi", data)
+}
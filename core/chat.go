@@ -0,0 +1,177 @@
+package core
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/sunshinev/go-space-chat/component"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 一次最多补发的聊天记录条数
+const redeliverLimit = 200
+
+// 聊天消息发给房间里能听到的客户
+// 每条消息分配递增的 id 和服务端时间，返回发出的消息；store 为 false 时不保存，id 为 0，用于上线、下线提示
+// 全局模式下视野外的人也能听到，旧协议的客户只在聊天窗口中显示，不添加这个 bot
+func (s *Core) sendChat(room *Room, client *Client, msg *pb.BotStatusRequest, shout, store bool) *pb.ChatMessage {
+	log.Printf("[%s] %s : %s", room.Name, msg.BotId+":"+msg.Name, msg.Msg)
+
+	x, y := WorldPos(msg)
+	record := &component.ChatRecord{
+		Time:    time.Now(),
+		Room:    room.Name,
+		BotId:   msg.BotId,
+		Name:    msg.Name,
+		Msg:     msg.Msg,
+		PosInfo: msg.PosInfo,
+		X:       x,
+		Y:       y,
+		Shout:   shout,
+	}
+	if store {
+		s.stamp(record)
+	}
+
+	chat := chatMessage(record)
+	viewers := map[*Client]bool{}
+	for _, c := range room.grid.Viewers(client, true) {
+		viewers[c] = true
+	}
+	near, far := []*Client{}, []*Client{}
+	for _, c := range s.audience(room, client, shout) {
+		if viewers[c] {
+			near = append(near, c)
+		} else {
+			far = append(far, c)
+		}
+	}
+	s.sendFrame(near, chatFrame(msg, chat))
+	if len(far) > 0 {
+		s.sendFrame(far, farChatFrame(msg, chat))
+	}
+
+	return chat
+}
+
+// 分配消息 id，有聊天记录存储时由存储分配，重启后也不会重复
+func (s *Core) stamp(record *component.ChatRecord) {
+	if s.MessageStore == nil {
+		s.lastChatId++
+		record.Id = s.lastChatId
+		return
+	}
+	if err := s.MessageStore.Append(record); err != nil {
+		log.Printf("chat store append err %v", err)
+	}
+}
+
+// 聊天记录转换成消息
+func chatMessage(r *component.ChatRecord) *pb.ChatMessage {
+	return &pb.ChatMessage{
+		BotId:   r.BotId,
+		Name:    r.Name,
+		Msg:     r.Msg,
+		PosInfo: r.PosInfo,
+		Shout:   r.Shout,
+		Id:      r.Id,
+		Time:    r.Time.UnixNano() / int64(time.Millisecond),
+	}
+}
+
+// 能听到该 bot 说话的客户：全局模式是房间里的所有人，附近模式是一定距离内的人
+func (s *Core) audience(room *Room, client *Client, shout bool) []*Client {
+	conf := s.Conf()
+	if conf.ChatMode != ChatProximity {
+		return room.members()
+	}
+	if shout {
+		return room.grid.Within(client, conf.ShoutRadius)
+	}
+	return room.grid.Within(client, conf.ChatRadius)
+}
+
+// 在 x, y 位置能否听到这条聊天记录，全局模式都能听到
+func (s *Core) hears(r *component.ChatRecord, x, y float64) bool {
+	conf := s.Conf()
+	if conf.ChatMode != ChatProximity {
+		return true
+	}
+	radius := conf.ChatRadius
+	if r.Shout {
+		radius = conf.ShoutRadius
+	}
+	return math.Hypot(r.X-x, r.Y-y) <= radius
+}
+
+// 回放房间最近的聊天记录，附近聊天模式只回放在当前位置能听到的
+func (s *Core) replay(room *Room, client *Client) {
+	history := s.Conf().History
+	if s.MessageStore == nil || history <= 0 {
+		return
+	}
+	x, y := WorldPos(room.grid.Status(client))
+
+	recent := []*pb.ChatMessage{}
+	for _, r := range s.MessageStore.Recent(room.Name, history) {
+		if s.hears(r, x, y) {
+			recent = append(recent, chatMessage(r))
+		}
+	}
+	if len(recent) > 0 {
+		client.Send(replayFrame(recent))
+	}
+}
+
+// 补发 id 范围内当前房间的聊天记录，to_id 为 0 表示到最新
+// 从 from_id 开始从旧到新补发，一次最多 redeliverLimit 条，没有补发完时 next_id 是下一次请求的 from_id
+// 在读协程中查询，不阻塞广播；附近聊天模式按最近一次上报的位置判断能否听到
+func (s *Core) redeliver(client *Client, req *pb.Redeliver) {
+	if s.MessageStore == nil {
+		client.Send(errorFrame(pb.ProtocolError_unexpected_payload, "chat store disabled"))
+		return
+	}
+	if req.ToId > 0 && req.ToId < req.FromId {
+		client.Send(errorFrame(pb.ProtocolError_bad_message, "to_id less than from_id"))
+		return
+	}
+
+	s.roomLock.RLock()
+	room, ok := s.clientRoom[client]
+	s.roomLock.RUnlock()
+	if !ok || client.last == nil {
+		client.Send(historyFrame(nil, 0))
+		return
+	}
+	// 多查一条，判断是否还有没补发的
+	q := &component.MessageQuery{
+		Room:   room.Name,
+		After:  req.FromId - 1,
+		Limit:  redeliverLimit + 1,
+		Oldest: true,
+	}
+	if req.ToId > 0 {
+		q.Before = req.ToId + 1
+	}
+	records, err := s.MessageStore.Query(q)
+	if err != nil {
+		log.Printf("chat store query err %v", err)
+		return
+	}
+
+	var next int64
+	if len(records) > redeliverLimit {
+		next = records[redeliverLimit].Id
+		records = records[:redeliverLimit]
+	}
+
+	x, y := WorldPos(client.last)
+	history := []*pb.ChatMessage{}
+	for _, r := range records {
+		if s.hears(r, x, y) {
+			history = append(history, chatMessage(r))
+		}
+	}
+	client.Send(historyFrame(history, next))
+}
@@ -0,0 +1,341 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sunshinev/go-space-chat/component"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 写超时
+const writeWait = 10 * time.Second
+
+// Client 客户端
+// 每个连接有独立的发送队列和写协程，慢连接不会拖慢其他连接
+// 连接断开后会话保留一段时间，客户端带着 session token 重连可以找回，断线期间的消息重连后补发
+type Client struct {
+	Conn  *websocket.Conn      // 当前连接，重连后替换
+	Info  *pb.BotStatusRequest // 连接信息：bot id、名称、地理位置
+	Token string               // 会话 token
+	Room  string               // 连接时请求加入的房间
+
+	last            *pb.BotStatusRequest  // 最近一次上报的状态，已经过滤和转义，只在读协程中使用
+	name            string                // 最近一次上报的名称，未过滤，只在读协程中使用
+	typingAt        time.Time             // 最近一次收到开始输入，只在读协程中使用
+	typing          bool                  // 转发了开始输入，还没有转发停止输入，只在读协程中使用
+	nick            string                // /nick 设置的昵称，只在读协程中使用
+	statusBucket    component.TokenBucket // 状态上报限流，只在读协程中使用
+	chatBucket      component.TokenBucket // 聊天限流，只在读协程中使用
+	redeliverBucket component.TokenBucket // 补发请求限流，只在读协程中使用
+	eventBucket     component.TokenBucket // 其他消息限流，只在读协程中使用
+	strikes         component.TokenBucket // 被限流的次数，超过后自动禁言或者断开，只在读协程中使用
+	moved           bool                  // 已经有接受的位置，只在读协程中使用
+	posX, posY      float64               // 最近一次接受的世界坐标，只在读协程中使用
+	posAt           time.Time             // 最近一次接受位置的时间，只在读协程中使用
+	moveBudget      float64               // 还可以移动的距离，只在读协程中使用
+	core            *Core
+	lock            sync.Mutex
+	queue           []*frame      // 待发送队列
+	dropped         int           // 队列追上之前丢弃的位置消息数
+	wake            chan struct{} // 通知写协程
+	done            chan struct{} // 当前连接的写协程退出
+	gen             int           // 第几次连接，用来判断宽限期是否已经失效
+	detached        bool          // 连接断开，等待重连
+	closed          bool          // 会话结束
+	role            Role          // 权限等级
+	ip              string        // 当前连接的 ip
+	since           time.Time     // 当前连接的建立时间
+	chats           int64         // 发送的聊天消息数
+	statuses        int64         // 上报的状态数
+	corrected       int64         // 移动太快被纠正的次数
+}
+
+// NewClient 创建客户端并启动写协程，bot id 和会话 token 由服务端分配
+func NewClient(s *Core, conn *websocket.Conn) *Client {
+	c := &Client{
+		Info: &pb.BotStatusRequest{
+			BotId: randomHex(8),
+		},
+		Token: randomHex(16),
+		core:  s,
+		wake:  make(chan struct{}, 1),
+	}
+	c.attach(conn)
+
+	return c
+}
+
+// 生成随机 id
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("rand read err %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// 绑定连接，启动写协程
+func (c *Client) attach(conn *websocket.Conn) {
+	c.Conn = conn
+	c.ip = remoteIP(conn)
+	c.since = time.Now()
+	c.done = make(chan struct{})
+	c.detached = false
+	c.gen++
+	go c.writeLoop(conn, c.done)
+}
+
+// 连接的 ip，不带端口
+func remoteIP(conn *websocket.Conn) string {
+	addr := conn.RemoteAddr().String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// IP 当前连接的 ip
+func (c *Client) IP() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.ip
+}
+
+// Role 权限等级
+func (c *Client) Role() Role {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.role
+}
+
+// SetRole 认证后设置权限等级
+func (c *Client) SetRole(role Role) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.role = role
+}
+
+// Kick 踢下线，告诉客户端原因后结束会话
+func (c *Client) Kick(reason string) {
+	c.lock.Lock()
+	conn := c.Conn
+	c.lock.Unlock()
+	// WriteControl 可以和写协程并发调用
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+	c.Close()
+}
+
+// BotId 服务端分配的 bot id
+// Info 由读协程加锁替换，其他协程通过 BotId、Name 加锁读取
+func (c *Client) BotId() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.Info.BotId
+}
+
+// Name 当前的名称
+func (c *Client) Name() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.Info.Name
+}
+
+// Welcome 告诉客户端服务端分配的 bot id 和会话 token
+func (c *Client) Welcome() {
+	c.Send(welcomeFrame(&pb.Welcome{
+		BotId:        c.BotId(),
+		SessionToken: c.Token,
+	}))
+}
+
+// Send 消息放入发送队列，不会阻塞
+// 队列满时丢弃最早的位置同步消息，丢弃过多或者队列里全是不能丢弃的消息，说明客户端消费太慢，断开连接
+// 断线期间位置同步没有意义，直接丢弃，其余消息留到重连后补发
+func (c *Client) Send(f *frame) {
+	if f == nil {
+		return
+	}
+	c.lock.Lock()
+	if c.closed || (c.detached && f.droppable) {
+		c.lock.Unlock()
+		return
+	}
+	conf := c.core.Conf()
+	if len(c.queue) >= conf.SendQueueSize {
+		if !c.dropOldest() || c.dropped > conf.SlowDropLimit {
+			botId, dropped := c.Info.BotId, c.dropped
+			c.lock.Unlock()
+			log.Printf("slow client %v, dropped %v, disconnect", botId, dropped)
+			c.Close()
+			return
+		}
+	}
+	c.queue = append(c.queue, f)
+	c.lock.Unlock()
+
+	c.notify()
+}
+
+// 通知写协程
+func (c *Client) notify() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// 丢弃最早的一条位置同步消息
+func (c *Client) dropOldest() bool {
+	for i, f := range c.queue {
+		if f.droppable {
+			c.queue = append(c.queue[:i], c.queue[i+1:]...)
+			c.dropped++
+			return true
+		}
+	}
+	return false
+}
+
+// Close 结束会话并关闭连接，读协程随之退出，走正常的下线流程
+func (c *Client) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.queue = nil
+	if !c.detached {
+		close(c.done)
+	}
+
+	err := c.Conn.Close()
+	if err != nil {
+		log.Printf("close websocket err %v", err)
+	}
+}
+
+// Closed 会话是否已经结束
+func (c *Client) Closed() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.closed
+}
+
+// Detach 连接断开，保留会话等待重连
+// 返回当前是第几次连接；ok 为 false 表示这个连接已经被重连的新连接替换了
+func (c *Client) Detach(conn *websocket.Conn) (gen int, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.Conn != conn {
+		return 0, false
+	}
+	if !c.detached && !c.closed {
+		c.detached = true
+		close(c.done)
+	}
+	_ = conn.Close()
+
+	return c.gen, true
+}
+
+// Resume 重连，换上新的连接，补发断线期间的消息
+// 旧连接还没发现断开时直接替换
+func (c *Client) Resume(conn *websocket.Conn) bool {
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return false
+	}
+	if !c.detached {
+		close(c.done)
+		_ = c.Conn.Close()
+	}
+	c.attach(conn)
+	c.lock.Unlock()
+
+	c.notify()
+	return true
+}
+
+// Expire 宽限期到了还没有重连，结束会话
+func (c *Client) Expire(gen int) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.detached || c.gen != gen {
+		return false
+	}
+	c.closed = true
+	c.queue = nil
+
+	return true
+}
+
+// 写协程，websocket 不支持并发写，当前连接的所有写操作都在这里
+// 定时发送 ping，客户端回复 pong 后延长读超时
+func (c *Client) writeLoop(conn *websocket.Conn, done chan struct{}) {
+	var ping <-chan time.Time
+	if c.core.PingInterval > 0 {
+		ticker := time.NewTicker(c.core.PingInterval)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+
+	for {
+		select {
+		case <-c.wake:
+		case <-ping:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			if err != nil {
+				log.Printf("conn write ping err %v", err)
+				_ = conn.Close()
+				return
+			}
+			continue
+		case <-done:
+			return
+		}
+		// 已经换了新连接，交给新的写协程
+		select {
+		case <-done:
+			c.notify()
+			return
+		default:
+		}
+
+		c.lock.Lock()
+		queue := c.queue
+		c.queue = nil
+		c.dropped = 0
+		c.lock.Unlock()
+
+		for i, f := range queue {
+			data := f.bytes(conn)
+			if data == nil {
+				continue
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err := conn.WriteMessage(websocket.BinaryMessage, data)
+			if err != nil {
+				log.Printf("conn write message err %v", err)
+				// 没发出去的放回队列，重连后补发
+				c.lock.Lock()
+				if !c.closed {
+					c.queue = append(queue[i:], c.queue...)
+				}
+				c.lock.Unlock()
+				_ = conn.Close()
+				c.notify()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"html"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 参数不对，回复命令用法
+var errUsage = errors.New("usage")
+
+// Command 聊天命令，以 / 开头的聊天消息不广播，交给命令处理
+// 命令在读协程中执行，需要访问房间、视野的交给广播协程执行
+type Command struct {
+	Name  string
+	Usage string // 用法
+	Help  string // 说明
+	Role  Role   // 最低权限
+	Args  int    // 最少参数个数
+	Run   func(ctx *commandContext) error
+}
+
+// 命令执行上下文
+type commandContext struct {
+	core   *Core
+	client *Client
+	name   string   // 发送者的昵称，未过滤
+	args   []string // 按空白分隔的参数
+	text   string   // 命令名之后的全部内容
+	chat   string   // 不为空表示命令要以聊天消息的形式发出的内容
+	shout  bool
+}
+
+// 回复只有自己能看到的提示
+func (c *commandContext) reply(format string, a ...interface{}) {
+	c.client.Send(replyFrame(c.client.last, fmt.Sprintf(format, a...)))
+}
+
+// 命令表
+var commands = map[string]*Command{}
+
+func registerCommand(cmd *Command) {
+	commands[cmd.Name] = cmd
+}
+
+// 是否是命令，// 开头的是普通消息
+func isCommand(msg string) bool {
+	return strings.HasPrefix(msg, "/") && !strings.HasPrefix(msg, "//")
+}
+
+// 执行命令，出错时回复发送者
+// 返回的 chat 不为空时，由调用方作为聊天消息发出
+func (s *Core) runCommand(client *Client, nick string, msg string) (ctx *commandContext, err error) {
+	fields := strings.Fields(msg)
+	name := strings.TrimPrefix(fields[0], "/")
+	ctx = &commandContext{
+		core:   s,
+		client: client,
+		name:   nick,
+		args:   fields[1:],
+		text:   strings.TrimSpace(strings.TrimPrefix(msg, fields[0])),
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		ctx.reply("未知命令 /%s，输入 /help 查看所有命令", name)
+		return ctx, fmt.Errorf("unknown command %v", name)
+	}
+	if client.Role() < cmd.Role {
+		ctx.reply("没有权限使用 /%s", name)
+		return ctx, fmt.Errorf("permission denied %v", name)
+	}
+	if len(ctx.args) < cmd.Args {
+		err = errUsage
+	} else {
+		err = cmd.Run(ctx)
+	}
+	if err == errUsage {
+		ctx.reply("用法：%s", cmd.Usage)
+		err = fmt.Errorf("usage: %v", cmd.Usage)
+	} else if err != nil {
+		ctx.reply("%v", err)
+	}
+	return ctx, err
+}
+
+func init() {
+	registerCommand(&Command{
+		Name:  "help",
+		Usage: "/help [命令]",
+		Help:  "查看命令",
+		Run:   cmdHelp,
+	})
+	registerCommand(&Command{
+		Name:  "nick",
+		Usage: "/nick 昵称",
+		Help:  "修改昵称",
+		Args:  1,
+		Run:   cmdNick,
+	})
+	registerCommand(&Command{
+		Name:  "me",
+		Usage: "/me 动作",
+		Help:  "以第三人称描述自己的动作",
+		Args:  1,
+		Run:   cmdMe,
+	})
+	registerCommand(&Command{
+		Name:  "who",
+		Usage: "/who",
+		Help:  "查看房间里的人",
+		Run:   cmdWho,
+	})
+	registerCommand(&Command{
+		Name:  "msg",
+		Usage: "/msg botid 消息",
+		Help:  "私聊",
+		Args:  2,
+		Run:   cmdMsg,
+	})
+	registerCommand(&Command{
+		Name:  "roll",
+		Usage: "/roll [最大值]",
+		Help:  "掷骰子，默认 1-100",
+		Run:   cmdRoll,
+	})
+	registerCommand(&Command{
+		Name:  "shout",
+		Usage: "/shout 消息",
+		Help:  "喊话，附近聊天模式下更远的人也能听到",
+		Args:  1,
+		Run:   cmdShout,
+	})
+	registerCommand(&Command{
+		Name:  "join",
+		Usage: "/join 房间名",
+		Help:  "进入房间",
+		Args:  1,
+		Run:   cmdJoin,
+	})
+}
+
+func cmdHelp(ctx *commandContext) error {
+	if len(ctx.args) > 0 {
+		cmd, ok := commands[strings.TrimPrefix(ctx.args[0], "/")]
+		if !ok || ctx.client.Role() < cmd.Role {
+			return fmt.Errorf("未知命令 %s", ctx.args[0])
+		}
+		ctx.reply("%s：%s", cmd.Usage, cmd.Help)
+		return nil
+	}
+
+	names := []string{}
+	for name, cmd := range commands {
+		if ctx.client.Role() >= cmd.Role {
+			names = append(names, "/"+name)
+		}
+	}
+	sort.Strings(names)
+	ctx.reply("可用命令：%s，输入 /help 命令 查看用法", strings.Join(names, " "))
+	return nil
+}
+
+// 昵称保存在服务端，之后上报的状态都使用这个昵称
+func cmdNick(ctx *commandContext) error {
+	if max := ctx.core.Conf().MaxNameLen; utf8.RuneCountInString(ctx.text) > max {
+		return fmt.Errorf("昵称最长 %d 个字", max)
+	}
+	c, s := ctx.client, ctx.core
+	c.nick = ctx.text
+	name := html.EscapeString(s.TextSafer.Filter(c.nick))
+	// 管理接口会读取名称
+	c.lock.Lock()
+	c.Info.Name = name
+	c.lock.Unlock()
+
+	// 立即同步新的昵称
+	if c.last != nil {
+		c.last.Name = name
+		messages <- &botMessage{client: c, status: proto.Clone(c.last).(*pb.BotStatusRequest)}
+	}
+	ctx.reply("昵称已修改为 %s", name)
+	return nil
+}
+
+func cmdMe(ctx *commandContext) error {
+	ctx.chat = "*" + ctx.name + " " + ctx.text
+	return nil
+}
+
+// 房间里的人在广播协程中查询
+func cmdWho(ctx *commandContext) error {
+	c, s := ctx.client, ctx.core
+	messages <- &botMessage{client: c, call: func() {
+		room, ok := s.clientRoom[c]
+		if !ok {
+			return
+		}
+		names := []string{}
+		for _, st := range room.grid.Snapshot(nil) {
+			names = append(names, fmt.Sprintf("%s(%s)", st.Name, st.BotId))
+		}
+		sort.Strings(names)
+		c.Send(replyFrame(room.grid.Status(c), fmt.Sprintf("房间 %s 共 %d 人：%s", room.Name, len(names), strings.Join(names, "，"))))
+	}}
+	return nil
+}
+
+func cmdMsg(ctx *commandContext) error {
+	if until, ok := ctx.core.muted(ctx.client); ok {
+		return fmt.Errorf("你已被禁言%s", until)
+	}
+	text := strings.TrimSpace(strings.TrimPrefix(ctx.text, ctx.args[0]))
+	msg := html.EscapeString(ctx.core.TextSafer.Filter(text))
+	if msg == "" {
+		return errUsage
+	}
+	messages <- &botMessage{client: ctx.client, direct: &pb.DirectMessage{
+		ToBotId:   ctx.args[0],
+		FromBotId: ctx.client.Info.BotId,
+		FromName:  ctx.client.Info.Name,
+		Msg:       msg,
+	}}
+	return nil
+}
+
+func cmdRoll(ctx *commandContext) error {
+	max := 100
+	if len(ctx.args) > 0 {
+		n, err := strconv.Atoi(ctx.args[0])
+		if err != nil || n < 1 {
+			return errUsage
+		}
+		max = n
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return err
+	}
+	ctx.chat = fmt.Sprintf("*%s 掷出了 %d (1-%d)", ctx.name, n.Int64()+1, max)
+	return nil
+}
+
+func cmdShout(ctx *commandContext) error {
+	ctx.chat = ctx.text
+	ctx.shout = true
+	return nil
+}
+
+func cmdJoin(ctx *commandContext) error {
+	if !roomNameRegexp.MatchString(ctx.args[0]) {
+		return fmt.Errorf("房间名只能是 1-32 个文字、数字、下划线或者中划线")
+	}
+	messages <- &botMessage{client: ctx.client, room: ctx.args[0]}
+	return nil
+}
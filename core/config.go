@@ -0,0 +1,149 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Config 运行时可以重新加载的配置
+// 启动参数作为默认值，-config 指定的 json 文件中出现的配置覆盖启动参数
+type Config struct {
+	SendQueueSize   int     `json:"send_queue"`       // 每个连接的发送队列长度
+	SlowDropLimit   int     `json:"slow_drop_limit"`  // 慢连接最多丢弃的位置消息数，超过后断开
+	ChatMode        string  `json:"chat_mode"`        // 聊天模式 global、proximity
+	ChatRadius      float64 `json:"chat_radius"`      // 附近聊天的范围
+	ShoutRadius     float64 `json:"shout_radius"`     // 附近聊天模式下喊话的范围
+	History         int     `json:"history"`          // 进入房间时回放的聊天记录条数，不超过启动时的值
+	AdminTokens     string  `json:"admin_tokens"`     // 管理员 token，多个用逗号分隔
+	ModeratorTokens string  `json:"moderator_tokens"` // 版主 token，多个用逗号分隔
+	StatusRate      float64 `json:"status_rate"`      // 每个连接每秒允许上报的状态数，0 不限制
+	StatusBurst     int     `json:"status_burst"`     // 每个连接允许突发的状态数
+	ChatRate        float64 `json:"chat_rate"`        // 每个连接每秒允许发送的聊天消息数，0 不限制
+	ChatBurst       int     `json:"chat_burst"`       // 每个连接允许突发的聊天消息数
+	IpStatusRate    float64 `json:"ip_status_rate"`   // 同一个 ip 的所有连接每秒允许上报的状态数，0 不限制
+	IpStatusBurst   int     `json:"ip_status_burst"`  // 同一个 ip 允许突发的状态数
+	IpChatRate      float64 `json:"ip_chat_rate"`     // 同一个 ip 的所有连接每秒允许发送的聊天消息数，0 不限制
+	IpChatBurst     int     `json:"ip_chat_burst"`    // 同一个 ip 允许突发的聊天消息数
+	RedeliverRate   float64 `json:"redeliver_rate"`   // 每个连接每秒允许请求补发的次数，每次都要扫描聊天记录文件，0 不限制
+	RedeliverBurst  int     `json:"redeliver_burst"`  // 每个连接允许突发的补发请求数
+	EventRate       float64 `json:"event_rate"`       // 每个连接每秒允许的其他消息数：正在输入、切换房间、不合法的消息等，0 不限制
+	EventBurst      int     `json:"event_burst"`      // 每个连接允许突发的其他消息数
+	AbuseLimit      int     `json:"abuse_limit"`      // 一分钟内被限流超过这个次数，聊天自动禁言，状态断开连接，0 关闭
+	AbuseMute       int     `json:"abuse_mute"`       // 自动禁言的秒数
+	MaxConns        int     `json:"max_conns"`        // 最多同时连接数，0 不限制
+	MaxConnsPerIp   int     `json:"max_conns_per_ip"` // 同一个 ip 最多同时连接数，白名单中的 ip 不限制，0 不限制
+	ReadLimit       int64   `json:"read_limit"`       // 单条消息的最大字节数，超过时断开连接，对之后的新连接生效
+	MaxNameLen      int     `json:"max_name_len"`     // 名字最多的字数
+	MaxMsgLen       int     `json:"max_msg_len"`      // 聊天消息最多的字数
+	MaxCoord        float64 `json:"max_coord"`        // 坐标绝对值的上限
+	MaxSpeed        float64 `json:"max_speed"`        // 每秒最多移动的距离，0 不校验
+	MoveSlack       float64 `json:"move_slack"`       // 允许突发移动的距离，抵消网络抖动
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() Config {
+	return Config{
+		SendQueueSize: 256,
+		SlowDropLimit: 1024,
+		ChatMode:      ChatGlobal,
+		ChatRadius:    600,
+		ShoutRadius:   3000,
+		History:       20,
+		StatusRate:    40,
+		StatusBurst:   80,
+		ChatRate:      1,
+		ChatBurst:     5,
+		// ip 的频率按 max_conns_per_ip 个正常连接算，同一个 NAT 后面的用户不会互相影响
+		IpStatusRate:   800,
+		IpStatusBurst:  1600,
+		IpChatRate:     20,
+		IpChatBurst:    100,
+		RedeliverRate:  0.2,
+		RedeliverBurst: 5,
+		EventRate:      5,
+		EventBurst:     10,
+		AbuseLimit:     30,
+		AbuseMute:      300,
+		MaxConns:       10000,
+		MaxConnsPerIp:  20,
+		ReadLimit:      4096,
+		MaxNameLen:     24, // 前端生成的默认名字 Guest 加随机串大约 16 个字
+		MaxMsgLen:      200,
+		MaxCoord:       1e7,
+		MaxSpeed:       450,
+		MoveSlack:      200,
+	}
+}
+
+// 校验配置
+func (c *Config) validate() error {
+	if c.ChatMode != ChatGlobal && c.ChatMode != ChatProximity {
+		return fmt.Errorf("unknown chat mode %v", c.ChatMode)
+	}
+	if c.SendQueueSize <= 0 {
+		return fmt.Errorf("send_queue must be positive")
+	}
+	if c.SlowDropLimit < 0 || c.ChatRadius < 0 || c.ShoutRadius < 0 || c.History < 0 {
+		return fmt.Errorf("slow_drop_limit, chat_radius, shout_radius and history must not be negative")
+	}
+	limits := []struct {
+		name  string
+		rate  float64
+		burst int
+	}{
+		{"status", c.StatusRate, c.StatusBurst},
+		{"chat", c.ChatRate, c.ChatBurst},
+		{"ip_status", c.IpStatusRate, c.IpStatusBurst},
+		{"ip_chat", c.IpChatRate, c.IpChatBurst},
+		{"redeliver", c.RedeliverRate, c.RedeliverBurst},
+		{"event", c.EventRate, c.EventBurst},
+	}
+	for _, l := range limits {
+		if l.rate < 0 {
+			return fmt.Errorf("%v_rate must not be negative", l.name)
+		}
+		if l.rate > 0 && l.burst < 1 {
+			return fmt.Errorf("%v_burst must be positive", l.name)
+		}
+	}
+	if c.AbuseLimit < 0 || c.AbuseMute < 0 {
+		return fmt.Errorf("abuse_limit and abuse_mute must not be negative")
+	}
+	if c.MaxConns < 0 || c.MaxConnsPerIp < 0 {
+		return fmt.Errorf("max_conns and max_conns_per_ip must not be negative")
+	}
+	if c.ReadLimit <= 0 || c.MaxNameLen <= 0 || c.MaxMsgLen <= 0 || c.MaxCoord <= 0 {
+		return fmt.Errorf("read_limit, max_name_len, max_msg_len and max_coord must be positive")
+	}
+	if c.MaxSpeed < 0 || c.MoveSlack < 0 {
+		return fmt.Errorf("max_speed and move_slack must not be negative")
+	}
+	return nil
+}
+
+// Conf 当前配置，各个协程都可以读取，不能修改
+func (s *Core) Conf() *Config {
+	conf, _ := s.config.Load().(*Config)
+	return conf
+}
+
+// LoadConfig 读取配置文件，校验通过后整体替换当前配置，失败时保留原来的配置
+func (s *Core) LoadConfig() error {
+	conf := s.Flags
+	if s.ConfigFile != "" {
+		b, err := ioutil.ReadFile(s.ConfigFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &conf); err != nil {
+			return err
+		}
+	}
+	if err := conf.validate(); err != nil {
+		return err
+	}
+	s.config.Store(&conf)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"net"
+	"net/http"
+)
+
+// 占用一个连接数，超过总数返回 503，超过单个 ip 的限制返回 429，白名单中的 ip 不受单个 ip 的限制
+func (s *Core) acquireConn(ip string) (int, bool) {
+	conf := s.Conf()
+	exempt := s.IpFilter.Allowed(net.ParseIP(ip))
+
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+	if conf.MaxConns > 0 && s.conns >= conf.MaxConns {
+		return http.StatusServiceUnavailable, false
+	}
+	if !exempt && conf.MaxConnsPerIp > 0 && s.ipConns[ip] >= conf.MaxConnsPerIp {
+		return http.StatusTooManyRequests, false
+	}
+	s.conns++
+	s.ipConns[ip]++
+	return http.StatusOK, true
+}
+
+// 连接结束，释放连接数
+func (s *Core) releaseConn(ip string) {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+	s.conns--
+	if s.ipConns[ip]--; s.ipConns[ip] <= 0 {
+		delete(s.ipConns, ip)
+	}
+}
+
+// 当前连接数
+func (s *Core) connCount() int {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+	return s.conns
+}
+
+// 踢掉黑名单中的会话，包括还没有上线的，返回踢掉的数量
+func (s *Core) kickDenied() int {
+	n := 0
+	s.sessions.Range(func(_, v interface{}) bool {
+		if c, ok := v.(*Client); ok && !c.Closed() && s.IpFilter.Denied(net.ParseIP(c.IP())) {
+			c.Kick("forbidden")
+			n++
+		}
+		return true
+	})
+	return n
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"math"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// Grid 九宫格视野管理
+// 按世界坐标把 bot 划分到固定大小的格子里，每个 bot 只能看到自己所在格子及周围 8 个格子
+// Grid 不是并发安全的，只能在广播协程中使用
+type Grid struct {
+	CellSize float64                       // 格子边长，即视野半径
+	cells    map[cell]map[*Client]struct{} // 格子内的客户端
+	entities map[*Client]*gridEntity       // 客户端所在格子及最新状态
+}
+
+// 格子坐标
+type cell struct {
+	X, Y int
+}
+
+// 格子中的 bot
+type gridEntity struct {
+	cell   cell
+	status *pb.BotStatusRequest // 最近一次广播的状态，用于进入视野时下发
+}
+
+// NewGrid ...
+func NewGrid(cellSize float64) *Grid {
+	return &Grid{
+		CellSize: cellSize,
+		cells:    map[cell]map[*Client]struct{}{},
+		entities: map[*Client]*gridEntity{},
+	}
+}
+
+// WorldPos bot 在世界中的坐标：画布左上角的世界坐标 + bot 在画布中的坐标
+func WorldPos(status *pb.BotStatusRequest) (float64, float64) {
+	return float64(status.RealX + status.X), float64(status.RealY + status.Y)
+}
+
+// 世界坐标所在的格子
+func (g *Grid) cellOf(x, y float64) cell {
+	return cell{
+		X: int(math.Floor(x / g.CellSize)),
+		Y: int(math.Floor(y / g.CellSize)),
+	}
+}
+
+// 九宫格
+func (c cell) around() []cell {
+	cells := make([]cell, 0, 9)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			cells = append(cells, cell{X: c.X + dx, Y: c.Y + dy})
+		}
+	}
+	return cells
+}
+
+// 两个格子的视野是否重叠
+func (c cell) sees(o cell) bool {
+	return abs(c.X-o.X) <= 1 && abs(c.Y-o.Y) <= 1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Move 更新 bot 的位置和状态
+// 返回因为这次移动新进入视野、离开视野的其他客户端
+func (g *Grid) Move(client *Client, status *pb.BotStatusRequest) (entered, left []*Client) {
+	newCell := g.cellOf(WorldPos(status))
+
+	e, ok := g.entities[client]
+	if !ok {
+		e = &gridEntity{cell: newCell}
+		g.entities[client] = e
+		g.add(client, newCell)
+		// 新加入，视野内的都是新进入的
+		entered = g.Viewers(client, false)
+	} else if e.cell != newCell {
+		oldCell := e.cell
+		g.remove(client, oldCell)
+		g.add(client, newCell)
+		e.cell = newCell
+
+		for _, c := range oldCell.around() {
+			if !newCell.sees(c) {
+				left = append(left, g.members(c)...)
+			}
+		}
+		for _, c := range newCell.around() {
+			if !oldCell.sees(c) {
+				for _, m := range g.members(c) {
+					if m != client {
+						entered = append(entered, m)
+					}
+				}
+			}
+		}
+	}
+	// 状态中不保留聊天内容
+	st := proto.Clone(status).(*pb.BotStatusRequest)
+	st.Msg = ""
+	e.status = st
+
+	return entered, left
+}
+
+// Remove 移除 bot，返回移除前能看到它的客户端
+func (g *Grid) Remove(client *Client) []*Client {
+	e, ok := g.entities[client]
+	if !ok {
+		return nil
+	}
+	viewers := g.Viewers(client, false)
+	g.remove(client, e.cell)
+	delete(g.entities, client)
+
+	return viewers
+}
+
+// Viewers 能看到该 bot 的客户端，即九宫格内的所有客户端
+func (g *Grid) Viewers(client *Client, withSelf bool) []*Client {
+	e, ok := g.entities[client]
+	if !ok {
+		return nil
+	}
+	viewers := []*Client{}
+	for _, c := range e.cell.around() {
+		for _, m := range g.members(c) {
+			if m != client || withSelf {
+				viewers = append(viewers, m)
+			}
+		}
+	}
+	return viewers
+}
+
+// Within 世界坐标距离该 bot 不超过 radius 的客户端，包括自己
+func (g *Grid) Within(client *Client, radius float64) []*Client {
+	e, ok := g.entities[client]
+	if !ok {
+		return nil
+	}
+	x, y := WorldPos(e.status)
+	within := []*Client{}
+	check := func(c *Client, st *pb.BotStatusRequest) {
+		cx, cy := WorldPos(st)
+		if math.Hypot(cx-x, cy-y) <= radius {
+			within = append(within, c)
+		}
+	}
+
+	// 半径覆盖的格子比 bot 还多时，直接遍历所有 bot
+	span := 2*math.Ceil(radius/g.CellSize) + 1
+	if span*span > float64(len(g.entities)) {
+		for c, ce := range g.entities {
+			check(c, ce.status)
+		}
+		return within
+	}
+	n := int(math.Ceil(radius / g.CellSize))
+	for dx := -n; dx <= n; dx++ {
+		for dy := -n; dy <= n; dy++ {
+			for c := range g.cells[cell{X: e.cell.X + dx, Y: e.cell.Y + dy}] {
+				check(c, g.entities[c].status)
+			}
+		}
+	}
+	return within
+}
+
+// Status 该 bot 最近一次的状态
+func (g *Grid) Status(client *Client) *pb.BotStatusRequest {
+	if e, ok := g.entities[client]; ok {
+		return e.status
+	}
+	return nil
+}
+
+// Snapshot 除自己以外所有 bot 的最新状态
+func (g *Grid) Snapshot(except *Client) []*pb.BotStatusRequest {
+	snapshot := make([]*pb.BotStatusRequest, 0, len(g.entities))
+	for client, e := range g.entities {
+		if client != except {
+			snapshot = append(snapshot, e.status)
+		}
+	}
+	return snapshot
+}
+
+// Visible 视野内除自己以外的 bot 的最新状态，视野外的 bot 收不到后续的移动和下线，不能下发
+func (g *Grid) Visible(client *Client) []*pb.BotStatusRequest {
+	viewers := g.Viewers(client, false)
+	visible := make([]*pb.BotStatusRequest, 0, len(viewers))
+	for _, c := range viewers {
+		visible = append(visible, g.entities[c].status)
+	}
+	return visible
+}
+
+func (g *Grid) members(c cell) []*Client {
+	clients := make([]*Client, 0, len(g.cells[c]))
+	for client := range g.cells[c] {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+func (g *Grid) add(client *Client, c cell) {
+	if _, ok := g.cells[c]; !ok {
+		g.cells[c] = map[*Client]struct{}{}
+	}
+	g.cells[c][client] = struct{}{}
+}
+
+func (g *Grid) remove(client *Client, c cell) {
+	delete(g.cells[c], client)
+	if len(g.cells[c]) == 0 {
+		delete(g.cells, c)
+	}
+}
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 测试用的客户端，只有 bot id
+func testClient(id string) *Client {
+	return &Client{Info: &pb.BotStatusRequest{BotId: id}}
+}
+
+func at(id string, x, y float32) *pb.BotStatusRequest {
+	return &pb.BotStatusRequest{BotId: id, X: x, Y: y}
+}
+
+// 排好序的 bot id，nil 和空的一样
+func clientIds(clients []*Client) []string {
+	ids := []string{}
+	for _, c := range clients {
+		ids = append(ids, c.BotId())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func statusIds(status []*pb.BotStatusRequest) []string {
+	ids := []string{}
+	for _, st := range status {
+		ids = append(ids, st.BotId)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// 按顺序放入 bot，返回 bot id 对应的客户端
+func place(g *Grid, status ...*pb.BotStatusRequest) map[string]*Client {
+	clients := map[string]*Client{}
+	for _, st := range status {
+		c, ok := clients[st.BotId]
+		if !ok {
+			c = testClient(st.BotId)
+			clients[st.BotId] = c
+		}
+		g.Move(c, st)
+	}
+	return clients
+}
+
+func TestGridMove(t *testing.T) {
+	// 格子边长 100，a 在 (0,0) 格，b 在 (1,0) 格，c 在 (3,0) 格，d 在 (-1,-1) 格
+	a, b, c, d := at("a", 50, 50), at("b", 150, 50), at("c", 350, 50), at("d", -50, -50)
+	cases := []struct {
+		name    string
+		setup   []*pb.BotStatusRequest
+		move    *pb.BotStatusRequest
+		entered []string
+		left    []string
+	}{
+		{
+			name:    "join",
+			setup:   []*pb.BotStatusRequest{b, c, d},
+			move:    a,
+			entered: []string{"b", "d"},
+		},
+		{
+			name:  "same cell",
+			setup: []*pb.BotStatusRequest{a, b, c, d},
+			move:  at("a", 99, 0),
+		},
+		{
+			name:    "next cell",
+			setup:   []*pb.BotStatusRequest{a, b, c, d},
+			move:    at("a", 250, 50),
+			entered: []string{"c"},
+			left:    []string{"d"},
+		},
+		{
+			name:  "far away",
+			setup: []*pb.BotStatusRequest{a, b, c, d, at("e", 1050, 1150)},
+			move:  at("a", 1050, 1050),
+			// c 本来就看不到
+			entered: []string{"e"},
+			left:    []string{"b", "d"},
+		},
+		{
+			// 负坐标向下取整，-1 在 (-1,0) 格
+			name:    "negative",
+			setup:   []*pb.BotStatusRequest{a, at("f", -150, 50)},
+			move:    at("a", -1, 50),
+			entered: []string{"f"},
+		},
+		{
+			// 画布左上角的世界坐标加画布中的坐标
+			name:    "real position",
+			setup:   []*pb.BotStatusRequest{a, c, d},
+			move:    &pb.BotStatusRequest{BotId: "a", X: 50, Y: 50, RealX: 200},
+			entered: []string{"c"},
+			left:    []string{"d"},
+		},
+		{
+			// 自己不算进入、离开
+			name:  "alone",
+			setup: []*pb.BotStatusRequest{a},
+			move:  at("a", 550, 550),
+		},
+	}
+	for _, tc := range cases {
+		g := NewGrid(100)
+		clients := place(g, tc.setup...)
+		client, ok := clients[tc.move.BotId]
+		if !ok {
+			client = testClient(tc.move.BotId)
+		}
+		entered, left := g.Move(client, tc.move)
+		if got, want := clientIds(entered), append([]string{}, tc.entered...); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: entered = %v, want %v", tc.name, got, want)
+		}
+		if got, want := clientIds(left), append([]string{}, tc.left...); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: left = %v, want %v", tc.name, got, want)
+		}
+		if st := g.Status(client); st == nil || st.X != tc.move.X || st.Y != tc.move.Y {
+			t.Errorf("%s: status = %v, want %v", tc.name, st, tc.move)
+		}
+	}
+}
+
+func TestGridStatusDropsMsg(t *testing.T) {
+	g := NewGrid(100)
+	st := at("a", 0, 0)
+	st.Msg = "hello"
+	clients := place(g, st)
+	if got := g.Status(clients["a"]).Msg; got != "" {
+		t.Errorf("status msg = %q, want empty", got)
+	}
+	if st.Msg != "hello" {
+		t.Errorf("Move modified the reported status")
+	}
+}
+
+func TestGridRemove(t *testing.T) {
+	g := NewGrid(100)
+	clients := place(g, at("a", 50, 50), at("b", 150, 50), at("c", 350, 50), at("d", -50, -50))
+	a := clients["a"]
+	if got, want := clientIds(g.Remove(a)), []string{"b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Remove = %v, want %v", got, want)
+	}
+	if g.Status(a) != nil {
+		t.Errorf("status after Remove = %v, want nil", g.Status(a))
+	}
+	if got, want := clientIds(g.Viewers(clients["b"], true)), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Viewers after Remove = %v, want %v", got, want)
+	}
+	if got := g.Remove(a); got != nil {
+		t.Errorf("second Remove = %v, want nil", got)
+	}
+	// 格子空了之后删除
+	g.Remove(clients["d"])
+	if _, ok := g.cells[cell{X: -1, Y: -1}]; ok {
+		t.Errorf("empty cell not deleted")
+	}
+	// 移除后重新加入，视野内的都是新进入的
+	if entered, _ := g.Move(a, at("a", 50, 50)); !reflect.DeepEqual(clientIds(entered), []string{"b"}) {
+		t.Errorf("entered after rejoin = %v, want [b]", clientIds(entered))
+	}
+}
+
+func TestGridVisible(t *testing.T) {
+	g := NewGrid(100)
+	clients := place(g, at("a", 50, 50), at("b", 150, 50), at("c", 350, 50), at("d", -50, -50))
+	cases := []struct {
+		id       string
+		visible  []string
+		withSelf []string
+	}{
+		{"a", []string{"b", "d"}, []string{"a", "b", "d"}},
+		{"b", []string{"a"}, []string{"a", "b"}},
+		{"c", []string{}, []string{"c"}},
+		{"d", []string{"a"}, []string{"a", "d"}},
+	}
+	for _, tc := range cases {
+		client := clients[tc.id]
+		if got := statusIds(g.Visible(client)); !reflect.DeepEqual(got, tc.visible) {
+			t.Errorf("Visible(%s) = %v, want %v", tc.id, got, tc.visible)
+		}
+		if got := clientIds(g.Viewers(client, false)); !reflect.DeepEqual(got, tc.visible) {
+			t.Errorf("Viewers(%s, false) = %v, want %v", tc.id, got, tc.visible)
+		}
+		if got := clientIds(g.Viewers(client, true)); !reflect.DeepEqual(got, tc.withSelf) {
+			t.Errorf("Viewers(%s, true) = %v, want %v", tc.id, got, tc.withSelf)
+		}
+	}
+	if got := g.Visible(testClient("x")); len(got) != 0 {
+		t.Errorf("Visible of unknown client = %v, want empty", got)
+	}
+	if got, want := statusIds(g.Snapshot(clients["a"])), []string{"b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot = %v, want %v", got, want)
+	}
+}
+
+func TestGridWithin(t *testing.T) {
+	status := []*pb.BotStatusRequest{
+		at("a", 0, 0),
+		at("b", 30, 40),  // 距离 50
+		at("c", 0, -100), // 距离 100，在相邻的格子里
+		at("d", 300, 0),
+	}
+	cases := []struct {
+		radius float64
+		want   []string
+	}{
+		{0, []string{"a"}},
+		{49, []string{"a"}},
+		{50, []string{"a", "b"}},
+		{100, []string{"a", "b", "c"}},
+		{300, []string{"a", "b", "c", "d"}},
+	}
+	// bot 少时遍历所有 bot，多时只遍历半径覆盖的格子，结果一样
+	for _, filler := range []int{0, 100} {
+		g := NewGrid(100)
+		all := append([]*pb.BotStatusRequest{}, status...)
+		for i := 0; i < filler; i++ {
+			all = append(all, at(fmt.Sprintf("z%d", i), 5000+float32(i)*10, 5000))
+		}
+		clients := place(g, all...)
+		for _, tc := range cases {
+			if got := clientIds(g.Within(clients["a"], tc.radius)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%d fillers: Within(a, %v) = %v, want %v", filler, tc.radius, got, tc.want)
+			}
+		}
+	}
+	if got := NewGrid(100).Within(testClient("x"), 100); got != nil {
+		t.Errorf("Within of unknown client = %v, want nil", got)
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"encoding/json"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sunshinev/go-space-chat/component"
+)
+
+const (
+	// 每页默认条数
+	messagesPageSize = 50
+	// 每页最多条数
+	messagesMaxPageSize = 500
+)
+
+type MessagesApiRsp struct {
+	Messages   []*component.ChatRecord `json:"messages"`
+	NextCursor int64                   `json:"next_cursor"` // 下一页的游标，0 表示没有更多了
+}
+
+// MessagesApi 查询聊天记录，从新到旧分页，只允许管理员访问
+// 参数：cursor 上一页返回的游标，limit 每页条数，since、until 时间范围（RFC3339），room、bot_id、name 精确匹配，q 消息内容包含的字符串
+func (s *Core) MessagesApi(w http.ResponseWriter, r *http.Request) {
+	if s.MessageStore == nil {
+		http.Error(w, "chat store disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	q := &component.MessageQuery{
+		Room:  query.Get("room"),
+		BotId: query.Get("bot_id"),
+		// 保存的名称和消息是转义过的，查询条件同样转义
+		Name:    html.EscapeString(query.Get("name")),
+		Keyword: html.EscapeString(query.Get("q")),
+		Limit:   messagesPageSize,
+	}
+	var err error
+	if v := query.Get("cursor"); v != "" {
+		if q.Before, err = strconv.ParseInt(v, 10, 64); err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if q.Limit > messagesMaxPageSize {
+			q.Limit = messagesMaxPageSize
+		}
+	}
+	if v := query.Get("since"); v != "" {
+		if q.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("until"); v != "" {
+		if q.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, "invalid until", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// 多查一条，判断是否还有下一页
+	limit := q.Limit
+	q.Limit++
+	records, err := s.MessageStore.Query(q)
+	if err != nil {
+		log.Printf("MessagesApi query %v", err)
+		http.Error(w, "query failed", http.StatusInternalServerError)
+		return
+	}
+	data := &MessagesApiRsp{
+		Messages: records,
+	}
+	if len(records) > limit {
+		data.Messages = records[:limit]
+		data.NextCursor = records[limit-1].Id
+	}
+
+	d, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("MessagesApi marsharl %v", err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	_, err = w.Write(d)
+	if err != nil {
+		log.Printf("MessagesApi write %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package core
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sunshinev/go-space-chat/component"
+)
+
+// Role 权限等级
+type Role int
+
+const (
+	RoleGuest Role = iota
+	RoleModerator
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleModerator:
+		return "moderator"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "guest"
+}
+
+// 用 token 认证，返回对应的权限等级，token 不对返回 false
+func (s *Core) authenticate(token string) (Role, bool) {
+	if token == "" {
+		return RoleGuest, false
+	}
+	check := func(tokens string) bool {
+		for _, t := range strings.Split(tokens, ",") {
+			t = strings.TrimSpace(t)
+			if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return true
+			}
+		}
+		return false
+	}
+	conf := s.Conf()
+	switch {
+	case check(conf.AdminTokens):
+		return RoleAdmin, true
+	case check(conf.ModeratorTokens):
+		return RoleModerator, true
+	}
+	return RoleGuest, false
+}
+
+// 认证并设置权限等级
+func (s *Core) auth(client *Client, token string) bool {
+	role, ok := s.authenticate(token)
+	if !ok {
+		s.Moderation.Audit(actorName(client), "auth_failed", component.IpTarget(client.IP()), time.Time{}, "")
+		return false
+	}
+	client.SetRole(role)
+	s.Moderation.Audit(actorName(client), "auth", component.BotTarget(client.BotId()), time.Time{}, role.String())
+	return true
+}
+
+// 审计日志中的操作人
+func actorName(client *Client) string {
+	return fmt.Sprintf("%s(%s)", client.Name(), client.BotId())
+}
+
+// 是否被禁言，返回到期时间的描述
+func (s *Core) muted(client *Client) (string, bool) {
+	sanction, ok := s.Moderation.Muted(component.BotTarget(client.BotId()), component.IpTarget(client.IP()))
+	if !ok {
+		return "", false
+	}
+	return untilText(sanction.Until), true
+}
+
+func untilText(until time.Time) string {
+	if until.IsZero() {
+		return "（永久）"
+	}
+	return "至 " + until.Format("2006-01-02 15:04:05")
+}
+
+// 解析处理对象，ip 地址按 ip 处理，其他的按 bot id 处理
+func parseTarget(arg string) string {
+	if ip := net.ParseIP(arg); ip != nil {
+		return component.IpTarget(ip.String())
+	}
+	return component.BotTarget(arg)
+}
+
+// 踢下线处理对象的所有连接，返回踢掉的连接数，已经断开的不算
+func (s *Core) kick(target string, reason string) int {
+	n := 0
+	s.Clients.Range(func(_, v interface{}) bool {
+		c, ok := v.(*Client)
+		if !ok || c.Closed() {
+			return true
+		}
+		if component.BotTarget(c.BotId()) == target || component.IpTarget(c.IP()) == target {
+			c.Kick(reason)
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+func init() {
+	registerCommand(&Command{
+		Name:  "auth",
+		Usage: "/auth token",
+		Help:  "认证为管理员",
+		Args:  1,
+		Run:   cmdAuth,
+	})
+	registerCommand(&Command{
+		Name:  "kick",
+		Usage: "/kick botid或ip [原因]",
+		Help:  "踢下线",
+		Role:  RoleModerator,
+		Args:  1,
+		Run:   cmdKick,
+	})
+	registerCommand(&Command{
+		Name:  "mute",
+		Usage: "/mute botid或ip 时长(如 10m) [原因]",
+		Help:  "禁言",
+		Role:  RoleModerator,
+		Args:  2,
+		Run:   cmdMute,
+	})
+	registerCommand(&Command{
+		Name:  "unmute",
+		Usage: "/unmute botid或ip",
+		Help:  "解除禁言",
+		Role:  RoleModerator,
+		Args:  1,
+		Run:   cmdUnmute,
+	})
+	registerCommand(&Command{
+		Name:  "ban",
+		Usage: "/ban botid或ip [时长] [原因]",
+		Help:  "封禁，不指定时长为永久",
+		Role:  RoleAdmin,
+		Args:  1,
+		Run:   cmdBan,
+	})
+	registerCommand(&Command{
+		Name:  "unban",
+		Usage: "/unban botid或ip",
+		Help:  "解除封禁",
+		Role:  RoleAdmin,
+		Args:  1,
+		Run:   cmdUnban,
+	})
+}
+
+func cmdAuth(ctx *commandContext) error {
+	if !ctx.core.auth(ctx.client, ctx.args[0]) {
+		return fmt.Errorf("认证失败")
+	}
+	ctx.reply("认证成功，权限 %s", ctx.client.Role())
+	return nil
+}
+
+// 命令参数中 from 之后的内容作为原因
+func reasonFrom(args []string, from int) string {
+	if len(args) <= from {
+		return ""
+	}
+	return strings.Join(args[from:], " ")
+}
+
+func cmdKick(ctx *commandContext) error {
+	target := parseTarget(ctx.args[0])
+	reason := reasonFrom(ctx.args, 1)
+	n := ctx.core.kick(target, "kicked")
+	ctx.core.Moderation.Audit(actorName(ctx.client), "kick", target, time.Time{}, reason)
+	ctx.reply("已踢下线 %s，共 %d 个连接", target, n)
+	return nil
+}
+
+func cmdMute(ctx *commandContext) error {
+	d, err := time.ParseDuration(ctx.args[1])
+	if err != nil || d <= 0 {
+		return errUsage
+	}
+	target := parseTarget(ctx.args[0])
+	until := time.Now().Add(d)
+	if err := ctx.core.Moderation.Mute(target, until, actorName(ctx.client), reasonFrom(ctx.args, 2)); err != nil {
+		return fmt.Errorf("保存失败 %v", err)
+	}
+	ctx.reply("已禁言 %s %s", target, untilText(until))
+	return nil
+}
+
+func cmdUnmute(ctx *commandContext) error {
+	target := parseTarget(ctx.args[0])
+	if err := ctx.core.Moderation.Unmute(target, actorName(ctx.client)); err != nil {
+		return fmt.Errorf("保存失败 %v", err)
+	}
+	ctx.reply("已解除禁言 %s", target)
+	return nil
+}
+
+// bot 当前连接的 ip，不在线返回 false
+func (s *Core) botIp(botId string) (string, bool) {
+	v, ok := s.Clients.Load(botId)
+	c, _ := v.(*Client)
+	if !ok || c == nil || c.Closed() {
+		return "", false
+	}
+	return c.IP(), true
+}
+
+// 封禁后立即踢下线
+// bot id 是每次连接时分配的，重连后就换了，所以按 bot id 封禁时同时封禁它当前的 ip
+func cmdBan(ctx *commandContext) error {
+	target := parseTarget(ctx.args[0])
+	var until time.Time
+	reasonAt := 1
+	if len(ctx.args) > 1 {
+		if d, err := time.ParseDuration(ctx.args[1]); err == nil && d > 0 {
+			until = time.Now().Add(d)
+			reasonAt = 2
+		}
+	}
+	targets := []string{target}
+	note := ""
+	if target == component.BotTarget(ctx.args[0]) {
+		if ip, ok := ctx.core.botIp(ctx.args[0]); ok {
+			targets = append(targets, component.IpTarget(ip))
+		} else {
+			note = "，对方不在线，bot id 重连后会变，只对本次会话有效"
+		}
+	}
+	n := 0
+	for _, t := range targets {
+		if err := ctx.core.Moderation.Ban(t, until, actorName(ctx.client), reasonFrom(ctx.args, reasonAt)); err != nil {
+			return fmt.Errorf("保存失败 %v", err)
+		}
+		n += ctx.core.kick(t, "banned")
+	}
+	ctx.reply("已封禁 %s %s，踢下线 %d 个连接%s", strings.Join(targets, "、"), untilText(until), n, note)
+	return nil
+}
+
+func cmdUnban(ctx *commandContext) error {
+	target := parseTarget(ctx.args[0])
+	if err := ctx.core.Moderation.Unban(target, actorName(ctx.client)); err != nil {
+		return fmt.Errorf("保存失败 %v", err)
+	}
+	ctx.reply("已解除封禁 %s", target)
+	return nil
+}
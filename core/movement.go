@@ -0,0 +1,47 @@
+package core
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 移动校验，只在读协程中调用
+// 可以移动的距离按 max_speed 随时间累积，最多累积 move_slack；超出的移动截断到允许的距离，并通知客户端纠正位置
+// 截断时保持 bot 在画布中的位置，只移动画布左上角
+func (s *Core) checkMove(client *Client, pbr *pb.BotStatusRequest) {
+	conf := s.Conf()
+	now := time.Now()
+	x, y := WorldPos(pbr)
+	if conf.MaxSpeed <= 0 || !client.moved {
+		client.moved = true
+		client.posX, client.posY, client.posAt = x, y, now
+		client.moveBudget = conf.MoveSlack
+		return
+	}
+
+	budget := client.moveBudget + conf.MaxSpeed*now.Sub(client.posAt).Seconds()
+	if budget > conf.MoveSlack {
+		budget = conf.MoveSlack
+	}
+	dx, dy := x-client.posX, y-client.posY
+	dist := math.Hypot(dx, dy)
+	if dist > budget {
+		x = client.posX + dx/dist*budget
+		y = client.posY + dy/dist*budget
+		pbr.RealX = float32(x) - pbr.X
+		pbr.RealY = float32(y) - pbr.Y
+		dist = budget
+		atomic.AddInt64(&client.corrected, 1)
+		client.Send(correctionFrame(&pb.PositionCorrection{
+			X:     pbr.X,
+			Y:     pbr.Y,
+			RealX: pbr.RealX,
+			RealY: pbr.RealY,
+		}))
+	}
+	client.posX, client.posY, client.posAt = x, y, now
+	client.moveBudget = budget - dist
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 不连接 websocket 的客户端，发送的消息留在队列里
+func moveClient(s *Core) *Client {
+	return &Client{core: s, wake: make(chan struct{}, 1), Info: &pb.BotStatusRequest{BotId: "a"}}
+}
+
+// 队列里的位置纠正
+func corrections(t *testing.T, c *Client) []*pb.PositionCorrection {
+	var list []*pb.PositionCorrection
+	for _, f := range c.queue {
+		env := &pb.Envelope{}
+		if err := proto.Unmarshal(f.envelope, env); err != nil {
+			t.Fatal(err)
+		}
+		if corr := env.GetCorrection(); corr != nil {
+			list = append(list, corr)
+		}
+	}
+	return list
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.5
+}
+
+func TestCheckMoveFirst(t *testing.T) {
+	s := NewCore()
+	c := moveClient(s)
+	// 第一次上报的位置直接接受
+	st := &pb.BotStatusRequest{X: 100, Y: 100, RealX: 1e6, RealY: -1e6}
+	s.checkMove(c, st)
+	if st.RealX != 1e6 || st.RealY != -1e6 || len(c.queue) != 0 {
+		t.Errorf("first position changed to %v, queue %d", st, len(c.queue))
+	}
+	if c.moveBudget != s.Conf().MoveSlack {
+		t.Errorf("budget = %v, want %v", c.moveBudget, s.Conf().MoveSlack)
+	}
+}
+
+func TestCheckMove(t *testing.T) {
+	// 默认每秒 450，最多累积 200
+	cases := []struct {
+		name     string
+		maxSpeed float64
+		elapsed  time.Duration
+		budget   float64 // 上次剩下的距离
+		to       float64 // 从 (0,0) 沿 x 轴移动到的世界坐标
+		want     float64
+		budgetAt float64 // 移动后剩下的距离
+	}{
+		{name: "within budget", maxSpeed: 450, budget: 200, to: 150, want: 150, budgetAt: 50},
+		{name: "over budget", maxSpeed: 450, budget: 50, to: 100, want: 50, budgetAt: 0},
+		{name: "accrual", maxSpeed: 450, elapsed: 200 * time.Millisecond, budget: 0, to: 100, want: 90, budgetAt: 0},
+		{name: "accrual and leftover", maxSpeed: 450, elapsed: 200 * time.Millisecond, budget: 50, to: 100, want: 100, budgetAt: 40},
+		// 停下来很久也只累积 move_slack
+		{name: "capped by slack", maxSpeed: 450, elapsed: time.Minute, budget: 0, to: 1000, want: 200, budgetAt: 0},
+		{name: "backwards", maxSpeed: 450, budget: 200, to: -300, want: -200, budgetAt: 0},
+		{name: "disabled", maxSpeed: 0, budget: 0, to: 1e6, want: 1e6, budgetAt: 200},
+	}
+	for _, tc := range cases {
+		s := NewCore()
+		conf := *s.Conf()
+		conf.MaxSpeed = tc.maxSpeed
+		s.config.Store(&conf)
+
+		c := moveClient(s)
+		c.moved = true
+		c.posAt = time.Now().Add(-tc.elapsed)
+		c.moveBudget = tc.budget
+
+		// 画布中的位置不变，只移动画布左上角
+		st := &pb.BotStatusRequest{X: 30, Y: 40, RealX: float32(tc.to) - 30, RealY: -40}
+		s.checkMove(c, st)
+		x, y := WorldPos(st)
+		if !near(x, tc.want) || !near(y, 0) {
+			t.Errorf("%s: moved to (%v, %v), want (%v, 0)", tc.name, x, y, tc.want)
+		}
+		if st.X != 30 || st.Y != 40 {
+			t.Errorf("%s: canvas position changed to (%v, %v)", tc.name, st.X, st.Y)
+		}
+		if !near(c.posX, tc.want) || !near(c.moveBudget, tc.budgetAt) {
+			t.Errorf("%s: pos %v budget %v, want %v budget %v", tc.name, c.posX, c.moveBudget, tc.want, tc.budgetAt)
+		}
+
+		// 截断时通知客户端纠正到截断后的位置
+		clamped := !near(tc.want, tc.to)
+		list := corrections(t, c)
+		if clamped != (len(list) == 1) || c.corrected != int64(len(list)) {
+			t.Errorf("%s: %d corrections, corrected %d, clamped %v", tc.name, len(list), c.corrected, clamped)
+			continue
+		}
+		if clamped {
+			want := &pb.PositionCorrection{X: st.X, Y: st.Y, RealX: st.RealX, RealY: st.RealY}
+			if !proto.Equal(list[0], want) {
+				t.Errorf("%s: correction %v, want %v", tc.name, list[0], want)
+			}
+		}
+	}
+}
+
+// 切换到当前所在的房间不能重置移动校验，否则可以先 /join 再瞬移
+func TestCheckMoveAfterJoin(t *testing.T) {
+	s := NewCore()
+	c := moveClient(s)
+	s.checkMove(c, &pb.BotStatusRequest{})
+	c.moveBudget = 0
+
+	if _, err := s.runCommand(c, "a", "/join lobby"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case m := <-messages:
+		if m.room != "lobby" {
+			t.Errorf("join message for room %q, want lobby", m.room)
+		}
+	default:
+		t.Fatal("no join message")
+	}
+
+	st := &pb.BotStatusRequest{RealX: 1e6}
+	s.checkMove(c, st)
+	if x, _ := WorldPos(st); x > 1 {
+		t.Errorf("moved to %v after /join, want clamped", x)
+	}
+	if c.corrected != 1 {
+		t.Errorf("corrected = %d, want 1", c.corrected)
+	}
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 通讯协议
+// 旧协议直接收发 botStatusRequest / botStatusResponse
+// 新协议在握手时通过 websocket 子协议协商，收发带版本号的 envelope，按 payload 类型区分消息
+// 过渡期两种协议同时支持，同一条消息两种编码各准备一份，写协程按连接的协议选择
+const (
+	// EnvelopeProtocol envelope 协议的 websocket 子协议名
+	EnvelopeProtocol = "star.envelope"
+	// ProtocolVersion 当前 envelope 协议版本
+	ProtocolVersion = 1
+)
+
+// 待发送的消息
+type frame struct {
+	legacy    []byte // 旧协议编码，nil 表示旧协议不支持这种消息
+	envelope  []byte // envelope 协议编码
+	droppable bool   // 位置同步可以丢弃，聊天、上下线不能丢弃
+}
+
+// 按连接的协议选择编码
+func (f *frame) bytes(conn *websocket.Conn) []byte {
+	if conn.Subprotocol() == EnvelopeProtocol {
+		return f.envelope
+	}
+	return f.legacy
+}
+
+// 编码消息，legacy 为 nil 表示旧协议不支持
+func newFrame(droppable bool, legacy *pb.BotStatusResponse, env *pb.Envelope) *frame {
+	var err error
+	f := &frame{droppable: droppable}
+	if legacy != nil {
+		f.legacy, err = proto.Marshal(legacy)
+		if err != nil {
+			log.Printf("proto marshal error %v %+v", err, legacy)
+			return nil
+		}
+	}
+	env.Version = ProtocolVersion
+	f.envelope, err = proto.Marshal(env)
+	if err != nil {
+		log.Printf("proto marshal error %v %+v", err, env)
+		return nil
+	}
+	return f
+}
+
+// 拼接多条消息
+// protobuf 的 repeated 字段拼接后解析等同于合并，envelope 中同一个 payload 拼接后也会合并，所以直接拼接编码结果
+func joinFrames(frames []*frame) *frame {
+	if len(frames) == 1 {
+		return frames[0]
+	}
+	legacy := make([][]byte, 0, len(frames))
+	envelope := make([][]byte, 0, len(frames))
+	for _, f := range frames {
+		legacy = append(legacy, f.legacy)
+		envelope = append(envelope, f.envelope)
+	}
+	return &frame{
+		legacy:    bytes.Join(legacy, nil),
+		envelope:  bytes.Join(envelope, nil),
+		droppable: frames[0].droppable,
+	}
+}
+
+// 状态同步
+func statusFrame(droppable bool, status ...*pb.BotStatusRequest) *frame {
+	resp := &pb.BotStatusResponse{
+		BotStatus: status,
+	}
+	return newFrame(droppable, resp, &pb.Envelope{
+		Payload: &pb.Envelope_Statuses{Statuses: resp},
+	})
+}
+
+// 分配的 bot id 和会话 token
+func welcomeFrame(welcome *pb.Welcome) *frame {
+	return newFrame(false, &pb.BotStatusResponse{Welcome: welcome}, &pb.Envelope{
+		Payload: &pb.Envelope_Welcome{Welcome: welcome},
+	})
+}
+
+// 聊天消息，旧协议用带 msg 的状态表示，没有消息 id 和时间，也不区分喊话
+func chatFrame(status *pb.BotStatusRequest, chat *pb.ChatMessage) *frame {
+	resp := &pb.BotStatusResponse{
+		BotStatus: []*pb.BotStatusRequest{status},
+	}
+	return newFrame(false, resp, &pb.Envelope{
+		Payload: &pb.Envelope_Chat{Chat: chat},
+	})
+}
+
+// 视野外的 bot 的聊天消息，旧协议在聊天状态后紧接着删除这个 bot，只在聊天窗口中显示
+// 视野外收不到这个 bot 后续的移动和下线，不删除的话会一直停在原地
+func farChatFrame(status *pb.BotStatusRequest, chat *pb.ChatMessage) *frame {
+	resp := &pb.BotStatusResponse{
+		BotStatus: []*pb.BotStatusRequest{status, closeStatus(status.BotId)},
+	}
+	return newFrame(false, resp, &pb.Envelope{
+		Payload: &pb.Envelope_Chat{Chat: chat},
+	})
+}
+
+// 聊天消息回执，旧协议不支持
+func ackFrame(ack *pb.ChatAck) *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_Ack{Ack: ack},
+	})
+}
+
+// 补发的聊天记录，next 不为 0 表示还有没发完的，旧协议不支持
+func historyFrame(messages []*pb.ChatMessage, next int64) *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_History{History: &pb.ChatHistory{Messages: messages, NextId: next}},
+	})
+}
+
+// 进入房间时回放的聊天记录，旧协议每条用带 msg 的状态紧跟下线状态表示，和视野外的聊天一样只在聊天窗口中显示
+// 要在视野快照之前发送，下线状态才不会删掉视野内的 bot
+func replayFrame(messages []*pb.ChatMessage) *frame {
+	legacy := &pb.BotStatusResponse{}
+	for _, m := range messages {
+		posInfo := m.PosInfo
+		if posInfo == nil {
+			posInfo = &pb.PInfo{}
+		}
+		legacy.BotStatus = append(legacy.BotStatus, &pb.BotStatusRequest{
+			BotId:   m.BotId,
+			Name:    m.Name,
+			Msg:     m.Msg,
+			PosInfo: posInfo,
+		}, closeStatus(m.BotId))
+	}
+	return newFrame(false, legacy, &pb.Envelope{
+		Payload: &pb.Envelope_History{History: &pb.ChatHistory{Messages: messages}},
+	})
+}
+
+// 私聊消息，旧协议用带 msg 的发送者状态表示，status 为 nil 表示旧协议不支持
+// far 表示发送者在接收者视野外，和 farChatFrame 一样紧跟一条下线状态，不留下幽灵
+func directFrame(dm *pb.DirectMessage, status *pb.BotStatusRequest, far bool) *frame {
+	var legacy *pb.BotStatusResponse
+	if status != nil {
+		legacy = &pb.BotStatusResponse{
+			BotStatus: []*pb.BotStatusRequest{status},
+		}
+		if far {
+			legacy.BotStatus = append(legacy.BotStatus, closeStatus(status.BotId))
+		}
+	}
+	return newFrame(false, legacy, &pb.Envelope{
+		Payload: &pb.Envelope_Direct{Direct: dm},
+	})
+}
+
+// 正在输入，旧协议不支持
+func typingFrame(botId string, typing bool) *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_Typing{Typing: &pb.Typing{BotId: botId, Typing: typing}},
+	})
+}
+
+// 位置纠正，旧协议不支持
+func correctionFrame(c *pb.PositionCorrection) *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_Correction{Correction: c},
+	})
+}
+
+// 只发给自己的提示，旧协议用带 msg 的自己的状态表示，status 为 nil 时旧协议不支持
+func replyFrame(status *pb.BotStatusRequest, msg string) *frame {
+	var legacy *pb.BotStatusResponse
+	if status != nil {
+		st := proto.Clone(status).(*pb.BotStatusRequest)
+		st.Msg = msg
+		legacy = &pb.BotStatusResponse{
+			BotStatus: []*pb.BotStatusRequest{st},
+		}
+	}
+	return newFrame(false, legacy, &pb.Envelope{
+		Payload: &pb.Envelope_Notice{Notice: &pb.ServerNotice{Msg: msg}},
+	})
+}
+
+// 公告的发送者，旧协议没有通知消息，用这个 bot 的聊天消息表示
+const announceBotId = "system"
+
+// 系统公告，旧协议用系统 bot 的聊天消息表示，紧接着删除这个 bot，只在聊天窗口中显示
+func announceFrame(msg string) *frame {
+	msg = html.EscapeString(msg)
+	resp := &pb.BotStatusResponse{
+		BotStatus: []*pb.BotStatusRequest{
+			{
+				BotId:   announceBotId,
+				Name:    "系统公告",
+				Msg:     msg,
+				PosInfo: &pb.PInfo{},
+			},
+			closeStatus(announceBotId),
+		},
+	}
+	return newFrame(false, resp, &pb.Envelope{
+		Payload: &pb.Envelope_Notice{Notice: &pb.ServerNotice{Msg: msg}},
+	})
+}
+
+// 服务端通知，旧协议不支持
+func noticeFrame(msg string) *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_Notice{Notice: &pb.ServerNotice{Msg: msg}},
+	})
+}
+
+// 协议错误，旧协议不支持
+func errorFrame(code pb.ProtocolErrorCodeType, msg string) *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_Error{Error: &pb.ProtocolError{Code: code, Msg: msg}},
+	})
+}
+
+// 协议错误
+type protocolError struct {
+	code  pb.ProtocolErrorCodeType
+	field string // 不合法的字段
+	msg   string
+}
+
+// 返回给客户端的协议错误
+func (e *protocolError) frame() *frame {
+	return newFrame(false, nil, &pb.Envelope{
+		Payload: &pb.Envelope_Error{Error: &pb.ProtocolError{Code: e.code, Field: e.field, Msg: e.msg}},
+	})
+}
+
+func (e *protocolError) Error() string {
+	return fmt.Sprintf("%v: %v", e.code, e.msg)
+}
+
+// 解析客户端消息，旧协议的消息也包装成 envelope，方便统一按 payload 分发
+func decodeMessage(conn *websocket.Conn, message []byte) (*pb.Envelope, error) {
+	if conn.Subprotocol() != EnvelopeProtocol {
+		pbr := &pb.BotStatusRequest{}
+		if err := proto.Unmarshal(message, pbr); err != nil {
+			return nil, &protocolError{code: pb.ProtocolError_bad_message, msg: err.Error()}
+		}
+		return &pb.Envelope{
+			Payload: &pb.Envelope_Status{Status: pbr},
+		}, nil
+	}
+
+	env := &pb.Envelope{}
+	if err := proto.Unmarshal(message, env); err != nil {
+		return nil, &protocolError{code: pb.ProtocolError_bad_message, msg: err.Error()}
+	}
+	if env.Version < 1 || env.Version > ProtocolVersion {
+		return nil, &protocolError{
+			code: pb.ProtocolError_unsupported_version,
+			msg:  fmt.Sprintf("unsupported version %v, server version %v", env.Version, ProtocolVersion),
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"log"
+	"time"
+
+	"github.com/sunshinev/go-space-chat/component"
+)
+
+// 自动处理在审计日志中的操作人
+const rateLimitActor = "rate-limit"
+
+// 状态上报限流，连接和 ip 都有令牌才放行，只在读协程中调用
+// 只有连接自己超过频率才记一次限流，ip 超过频率时同一 ip 下的其他人也会被拒绝，不能算到这个连接头上
+func (s *Core) allowStatus(client *Client) bool {
+	conf := s.Conf()
+	now := time.Now()
+	if client.statusBucket.Allow(conf.StatusRate, conf.StatusBurst, now) {
+		return s.ipStatus.Allow(client.IP(), conf.IpStatusRate, conf.IpStatusBurst, now)
+	}
+	// 持续刷状态的断开连接
+	if s.abusing(client, now) && !client.Closed() {
+		s.Moderation.Audit(rateLimitActor, "kick", component.BotTarget(client.BotId()), time.Time{}, "flooding status")
+		client.Kick("rate limited")
+	}
+	return false
+}
+
+// 聊天限流，包括聊天命令和私聊，只在读协程中调用，和状态一样只有连接自己超过频率才记限流
+func (s *Core) allowChat(client *Client) bool {
+	conf := s.Conf()
+	now := time.Now()
+	if client.chatBucket.Allow(conf.ChatRate, conf.ChatBurst, now) {
+		return s.ipChat.Allow(client.IP(), conf.IpChatRate, conf.IpChatBurst, now)
+	}
+	// 持续刷屏的自动禁言，已经禁言的不再延长
+	if s.abusing(client, now) && conf.AbuseMute > 0 {
+		if _, ok := s.muted(client); !ok {
+			until := now.Add(time.Duration(conf.AbuseMute) * time.Second)
+			target := component.BotTarget(client.BotId())
+			if err := s.Moderation.Mute(target, until, rateLimitActor, "flooding chat"); err != nil {
+				log.Printf("auto mute %v err %v", target, err)
+			}
+			client.Send(replyFrame(client.last, "发言太频繁，已被禁言"+untilText(until)))
+		}
+	}
+	return false
+}
+
+// 补发聊天记录限流，每次补发都要扫描聊天记录文件，只在读协程中调用
+func (s *Core) allowRedeliver(client *Client) bool {
+	conf := s.Conf()
+	return s.allowOrKick(client, &client.redeliverBucket, conf.RedeliverRate, conf.RedeliverBurst, "redeliver")
+}
+
+// 其他消息限流：正在输入、切换房间、不合法和不支持的消息，只在读协程中调用
+func (s *Core) allowEvent(client *Client) bool {
+	conf := s.Conf()
+	return s.allowOrKick(client, &client.eventBucket, conf.EventRate, conf.EventBurst, "events")
+}
+
+// 连接自己的令牌桶，持续超过频率的断开连接
+func (s *Core) allowOrKick(client *Client, bucket *component.TokenBucket, rate float64, burst int, what string) bool {
+	now := time.Now()
+	if bucket.Allow(rate, burst, now) {
+		return true
+	}
+	if s.abusing(client, now) && !client.Closed() {
+		s.Moderation.Audit(rateLimitActor, "kick", component.BotTarget(client.BotId()), time.Time{}, "flooding "+what)
+		client.Kick("rate limited")
+	}
+	return false
+}
+
+// 记一次限流，一分钟内超过 abuse_limit 次返回 true 并重新计数
+func (s *Core) abusing(client *Client, now time.Time) bool {
+	limit := s.Conf().AbuseLimit
+	if limit <= 0 {
+		return false
+	}
+	if client.strikes.Allow(float64(limit)/60, limit, now) {
+		return false
+	}
+	client.strikes.Reset()
+	return true
+}
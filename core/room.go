@@ -0,0 +1,150 @@
+package core
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// DefaultRoom 没有指定房间时进入的房间
+const DefaultRoom = "lobby"
+
+// 房间名：1-32 个文字、数字、下划线或者中划线
+var roomNameRegexp = regexp.MustCompile(`^[\p{L}\p{N}_-]{1,32}$`)
+
+// Room 房间，每个房间是一个独立的空间，位置同步和聊天只在房间内广播
+// 房间在有人加入时创建，所有人离开后删除
+// grid、pending 只在广播协程中使用，clients 的增删在广播协程中加锁进行
+type Room struct {
+	Name    string
+	grid    *Grid
+	pending map[*Client]*pb.BotStatusRequest // 待合并下发的位置同步，每个 bot 只保留最新一条
+	typing  map[*Client]time.Time            // 正在输入的 bot 及过期时间
+	clients map[*Client]struct{}
+}
+
+// 房间名不合法时进入默认房间
+func roomName(name string) string {
+	if !roomNameRegexp.MatchString(name) {
+		return DefaultRoom
+	}
+	return name
+}
+
+// 加入房间，房间不存在时创建
+func (s *Core) joinRoom(client *Client, name string) *Room {
+	s.roomLock.Lock()
+	defer s.roomLock.Unlock()
+
+	room, ok := s.rooms[name]
+	if !ok {
+		room = &Room{
+			Name:    name,
+			grid:    NewGrid(s.ViewRange),
+			pending: map[*Client]*pb.BotStatusRequest{},
+			typing:  map[*Client]time.Time{},
+			clients: map[*Client]struct{}{},
+		}
+		s.rooms[name] = room
+		log.Printf("room %v created", name)
+	}
+	room.clients[client] = struct{}{}
+	s.clientRoom[client] = room
+
+	return room
+}
+
+// 离开房间，房间没人了就删除
+func (s *Core) leaveRoom(client *Client) {
+	s.roomLock.Lock()
+	defer s.roomLock.Unlock()
+
+	room, ok := s.clientRoom[client]
+	if !ok {
+		return
+	}
+	delete(room.clients, client)
+	delete(room.pending, client)
+	delete(room.typing, client)
+	delete(s.clientRoom, client)
+
+	if len(room.clients) == 0 {
+		delete(s.rooms, room.Name)
+		log.Printf("room %v removed", room.Name)
+	}
+}
+
+// 切换房间：旧房间里的人删除该 bot，自己删除旧房间里的所有 bot，再以最近的状态加入新房间
+func (s *Core) switchRoom(client *Client, name string) {
+	old, ok := s.clientRoom[client]
+	if ok && old.Name == name {
+		return
+	}
+
+	var last *pb.BotStatusRequest
+	if ok {
+		last = old.grid.Status(client)
+		others := []*pb.BotStatusRequest{}
+		for _, st := range old.grid.Snapshot(client) {
+			others = append(others, closeStatus(st.BotId))
+		}
+		s.send([]*Client{client}, false, others...)
+		s.send(old.grid.Remove(client), false, closeStatus(client.BotId()))
+		s.leaveRoom(client)
+	}
+
+	s.joinRoom(client, name)
+	client.Send(noticeFrame("已进入房间 " + name))
+	if last != nil {
+		s.handleMessage(&botMessage{client: client, status: last})
+	}
+}
+
+// 房间里的所有客户
+func (r *Room) members() []*Client {
+	clients := make([]*Client, 0, len(r.clients))
+	for c := range r.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+type RoomApiRsp struct {
+	Name   string `json:"name"`
+	Online int    `json:"online"`
+}
+
+// RoomsApi 房间列表及在线人数
+func (s *Core) RoomsApi(w http.ResponseWriter, r *http.Request) {
+	data := []RoomApiRsp{}
+
+	s.roomLock.RLock()
+	for name, room := range s.rooms {
+		data = append(data, RoomApiRsp{
+			Name:   name,
+			Online: len(room.clients),
+		})
+	}
+	s.roomLock.RUnlock()
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Name < data[j].Name
+	})
+
+	d, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("RoomsApi marsharl %v", err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	_, err = w.Write(d)
+	if err != nil {
+		log.Printf("RoomsApi write %v", err)
+	}
+}
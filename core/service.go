@@ -3,10 +3,16 @@ package core
 import (
 	"encoding/json"
 	"flag"
+	"fmt"
 	"html"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/websocket"
@@ -18,29 +24,146 @@ import (
 type Core struct {
 	SocketAddr       string
 	WebAddr          string
+	ViewRange        float64       // 视野半径，九宫格格子边长
+	TickRate         int           // 每秒合并下发位置同步的次数
+	ChatStore        string        // 聊天记录文件，为空不保存
+	ConfigFile       string        // 可以重新加载的配置文件
+	Flags            Config        // 启动参数中可以重新加载的配置
+	config           atomic.Value  // 当前配置 *Config
+	SessionGrace     time.Duration // 断线后保留会话的时间，期间可以重连找回
+	PingInterval     time.Duration // 心跳间隔
+	PongWait         time.Duration // 多久没有收到 pong 认为连接已经断开
 	WebsocketUpgrade websocket.Upgrader
-	ConnMutex        sync.RWMutex
-	Clients          sync.Map // 客户端集合
+	Clients          sync.Map // 客户端集合 bot id => *Client
+	sessions         sync.Map // 会话 token => *Client
+	rooms            map[string]*Room
+	clientRoom       map[*Client]*Room // 客户所在的房间
+	roomLock         sync.RWMutex
+	conns            int            // 当前连接数
+	ipConns          map[string]int // ip => 连接数
+	connLock         sync.Mutex
+	ipStatus         *component.RateLimiter // 按 ip 限制状态上报
+	ipChat           *component.RateLimiter // 按 ip 限制聊天
+	reaped           int64                  // 心跳超时被清理的连接数
+	lastChatId       int64                  // 没有保存聊天记录时用来分配消息 id，只在广播协程中使用
+	ModerationFile   string                 // 禁言、封禁名单文件
+	AuditLog         string                 // 管理操作审计日志
+	Moderation       *component.Moderation
+	IpFilterFile     string // ip 黑白名单文件
+	IpFilter         *component.IpFilter
 	TextSafer        component.TextSafe
+	WordsPoll        time.Duration // 检查敏感词库文件修改的间隔
+	MessageStore     component.MessageStore
 	loginChart       *component.LoginChart
 	IpSearch         *component.IpSearch
 }
 
 // NewCore ...
 func NewCore() *Core {
-	return &Core{}
+	s := &Core{
+		Flags:      DefaultConfig(),
+		rooms:      map[string]*Room{},
+		clientRoom: map[*Client]*Room{},
+		ipConns:    map[string]int{},
+		Moderation: component.NewModeration(),
+		IpFilter:   component.NewIpFilter(),
+		ipStatus:   component.NewRateLimiter(),
+		ipChat:     component.NewRateLimiter(),
+	}
+	conf := s.Flags
+	s.config.Store(&conf)
+
+	return s
+}
+
+// 聊天模式
+const (
+	// ChatGlobal 聊天消息房间内所有人都能收到
+	ChatGlobal = "global"
+	// ChatProximity 聊天消息只有附近的人能收到，喊话的范围更大
+	ChatProximity = "proximity"
+)
+
+// 广播消息
+type botMessage struct {
+	client *Client
+	status *pb.BotStatusRequest
+	shout  bool              // 喊话
+	greet  bool              // 上线提示，不保存到聊天记录
+	ack    *pb.ChatAck       // 不为空表示发送者需要回执，消息 id 和时间在广播时填写
+	room   string            // 不为空表示切换房间
+	direct *pb.DirectMessage // 不为空表示私聊
+	typing *pb.Typing        // 不为空表示开始、停止输入
+	call   func()            // 不为空表示在广播协程中执行
 }
 
 // 广播消息缓冲通道
-var messages = make(chan *pb.BotStatusRequest, 1000)
+var messages = make(chan *botMessage, 1000)
 
 func (s *Core) Run() {
 	// 启动参数
-	s.SocketAddr = *flag.String("socket_addr", ":9000", "socket address")
-	s.WebAddr = *flag.String("web_addr", ":80", "http service address")
+	flag.StringVar(&s.SocketAddr, "socket_addr", ":9000", "socket address")
+	flag.StringVar(&s.WebAddr, "web_addr", ":80", "http service address")
+	flag.Float64Var(&s.ViewRange, "view_range", 1000, "bot view range in world pixels")
+	flag.IntVar(&s.TickRate, "tick_rate", 20, "position broadcast ticks per second")
+	flag.StringVar(&s.ChatStore, "chat_store", "data/chat.log", "chat history file, empty to disable")
+	flag.StringVar(&s.ConfigFile, "config", "", "json config file overriding the reloadable flags below")
+	flag.IntVar(&s.Flags.SendQueueSize, "send_queue", s.Flags.SendQueueSize, "outbound queue size per connection")
+	flag.IntVar(&s.Flags.SlowDropLimit, "slow_drop_limit", s.Flags.SlowDropLimit, "dropped position updates before a slow connection is closed")
+	flag.StringVar(&s.Flags.ChatMode, "chat_mode", s.Flags.ChatMode, "chat delivery mode: global or proximity")
+	flag.Float64Var(&s.Flags.ChatRadius, "chat_radius", s.Flags.ChatRadius, "proximity chat radius in world pixels")
+	flag.Float64Var(&s.Flags.ShoutRadius, "shout_radius", s.Flags.ShoutRadius, "proximity chat shout radius in world pixels")
+	flag.IntVar(&s.Flags.History, "history", s.Flags.History, "recent chat messages replayed on join")
+	flag.StringVar(&s.Flags.AdminTokens, "admin_tokens", "", "comma separated admin tokens")
+	flag.StringVar(&s.Flags.ModeratorTokens, "moderator_tokens", "", "comma separated moderator tokens")
+	flag.Float64Var(&s.Flags.StatusRate, "status_rate", s.Flags.StatusRate, "position updates per second per connection, 0 for no limit")
+	flag.IntVar(&s.Flags.StatusBurst, "status_burst", s.Flags.StatusBurst, "position update burst per connection")
+	flag.Float64Var(&s.Flags.ChatRate, "chat_rate", s.Flags.ChatRate, "chat messages per second per connection, 0 for no limit")
+	flag.IntVar(&s.Flags.ChatBurst, "chat_burst", s.Flags.ChatBurst, "chat message burst per connection")
+	flag.Float64Var(&s.Flags.IpStatusRate, "ip_status_rate", s.Flags.IpStatusRate, "position updates per second per ip, 0 for no limit")
+	flag.IntVar(&s.Flags.IpStatusBurst, "ip_status_burst", s.Flags.IpStatusBurst, "position update burst per ip")
+	flag.Float64Var(&s.Flags.IpChatRate, "ip_chat_rate", s.Flags.IpChatRate, "chat messages per second per ip, 0 for no limit")
+	flag.IntVar(&s.Flags.IpChatBurst, "ip_chat_burst", s.Flags.IpChatBurst, "chat message burst per ip")
+	flag.Float64Var(&s.Flags.RedeliverRate, "redeliver_rate", s.Flags.RedeliverRate, "chat redeliver requests per second per connection, 0 for no limit")
+	flag.IntVar(&s.Flags.RedeliverBurst, "redeliver_burst", s.Flags.RedeliverBurst, "chat redeliver request burst per connection")
+	flag.Float64Var(&s.Flags.EventRate, "event_rate", s.Flags.EventRate, "other messages (typing, room switches, invalid messages) per second per connection, 0 for no limit")
+	flag.IntVar(&s.Flags.EventBurst, "event_burst", s.Flags.EventBurst, "other message burst per connection")
+	flag.IntVar(&s.Flags.AbuseLimit, "abuse_limit", s.Flags.AbuseLimit, "rate limited messages per minute before auto mute or disconnect, 0 to disable")
+	flag.IntVar(&s.Flags.AbuseMute, "abuse_mute", s.Flags.AbuseMute, "auto mute seconds for chat flooding")
+	flag.StringVar(&s.ModerationFile, "moderation_file", "data/moderation.json", "mute and ban list file, empty to keep in memory")
+	flag.StringVar(&s.AuditLog, "audit_log", "data/audit.log", "moderation audit log, empty to disable")
+	flag.IntVar(&s.Flags.MaxConns, "max_conns", s.Flags.MaxConns, "max concurrent connections, 0 for no limit")
+	flag.IntVar(&s.Flags.MaxConnsPerIp, "max_conns_per_ip", s.Flags.MaxConnsPerIp, "max concurrent connections per ip, 0 for no limit")
+	flag.Int64Var(&s.Flags.ReadLimit, "read_limit", s.Flags.ReadLimit, "max inbound message size in bytes")
+	flag.IntVar(&s.Flags.MaxNameLen, "max_name_len", s.Flags.MaxNameLen, "max name length in characters")
+	flag.IntVar(&s.Flags.MaxMsgLen, "max_msg_len", s.Flags.MaxMsgLen, "max chat message length in characters")
+	flag.Float64Var(&s.Flags.MaxCoord, "max_coord", s.Flags.MaxCoord, "max absolute coordinate value")
+	flag.Float64Var(&s.Flags.MaxSpeed, "max_speed", s.Flags.MaxSpeed, "max movement speed in world pixels per second, 0 to disable")
+	flag.Float64Var(&s.Flags.MoveSlack, "move_slack", s.Flags.MoveSlack, "movement burst allowance in world pixels")
+	flag.StringVar(&s.TextSafer.Path, "words_file", "config/words_filter.txt", "sensitive word list, one word per line")
+	flag.StringVar(&s.TextSafer.HomoglyphsPath, "words_homoglyphs", "config/homoglyphs.txt", "homoglyph table used to normalize text before word matching")
+	flag.BoolVar(&s.TextSafer.Pinyin, "words_pinyin", false, "also match sensitive words by pinyin")
+	flag.StringVar(&s.TextSafer.PinyinPath, "words_pinyin_file", "config/pinyin.txt", "pinyin table used by -words_pinyin")
+	flag.DurationVar(&s.WordsPoll, "words_poll", 5*time.Second, "how often to check the word list for changes, 0 to disable")
+	flag.StringVar(&s.IpFilterFile, "ip_filter_file", "data/ip_filter.json", "ip allow and deny list file, empty to keep in memory")
+	flag.DurationVar(&s.SessionGrace, "session_grace", 30*time.Second, "how long a dropped session can be resumed, 0 to disable")
+	flag.DurationVar(&s.PingInterval, "ping_interval", 20*time.Second, "websocket ping interval, 0 to disable")
+	flag.DurationVar(&s.PongWait, "pong_wait", 60*time.Second, "close connections without pong for this long, 0 to disable")
 
 	flag.Parse()
 
+	// 不能重新加载的参数，启动时校验
+	if s.TickRate <= 0 {
+		log.Fatalf("tick_rate must be positive, got %d", s.TickRate)
+	}
+	if !(s.ViewRange > 0) || math.IsInf(s.ViewRange, 0) {
+		log.Fatalf("view_range must be a positive number, got %v", s.ViewRange)
+	}
+
+	if err := s.LoadConfig(); err != nil {
+		log.Fatalf("load config err %v", err)
+	}
+
 	log.Printf("socket port %s", s.SocketAddr)
 	log.Printf("web port %s", s.WebAddr)
 
@@ -49,14 +172,46 @@ func (s *Core) Run() {
 	if err != nil {
 		log.Fatalf("text safe new err %v", err)
 	}
+	// 词库文件修改后自动重新加载
+	if s.WordsPoll > 0 {
+		SafeGo(func() {
+			s.TextSafer.Watch(s.WordsPoll)
+		})
+	}
 	// 初始日志记录
 	s.loginChart = component.InitLoginChart()
 	// 初始化ip转换
 	s.IpSearch = component.InitIpSearch()
+	// 禁言、封禁名单
+	s.Moderation, err = component.OpenModeration(s.ModerationFile, s.AuditLog)
+	if err != nil {
+		log.Fatalf("open moderation err %v", err)
+	}
+	// ip 黑白名单
+	s.IpFilter, err = component.OpenIpFilter(s.IpFilterFile)
+	if err != nil {
+		log.Fatalf("open ip filter err %v", err)
+	}
+	// 聊天记录
+	if s.ChatStore != "" {
+		s.MessageStore, err = component.OpenFileMessageStore(s.ChatStore, s.Conf().History)
+		if err != nil {
+			log.Fatalf("open chat store err %v", err)
+		}
+	}
 
 	// 启动web服务
 	SafeGo(func() {
 		http.HandleFunc("/login_charts", s.ChartDataApi)
+		http.HandleFunc("/stats", s.StatsApi)
+		http.HandleFunc("/rooms", s.RoomsApi)
+		http.HandleFunc("/api/messages", s.adminOnly(s.MessagesApi))
+		http.HandleFunc("/admin/clients", s.adminOnly(s.AdminClientsApi))
+		http.HandleFunc("/admin/kick", s.adminOnly(s.AdminKickApi))
+		http.HandleFunc("/admin/announce", s.adminOnly(s.AdminAnnounceApi))
+		http.HandleFunc("/admin/reload", s.adminOnly(s.AdminReloadApi))
+		http.HandleFunc("/admin/ip_filter", s.adminOnly(s.AdminIpFilterApi))
+		http.HandleFunc("/admin/words", s.adminOnly(s.AdminWordsApi))
 		http.Handle("/", http.FileServer(http.Dir("web_resource/dist/")))
 
 		err := http.ListenAndServe(s.WebAddr, nil)
@@ -76,8 +231,9 @@ func (s *Core) Run() {
 		log.Println(http.ListenAndServe(":6060", nil))
 	})
 
-	// 监听websocket
+	// 监听websocket，/ws/房间名 直接进入指定房间
 	http.HandleFunc("/ws", s.websocketUpgrade)
+	http.HandleFunc("/ws/", s.websocketUpgrade)
 
 	err = http.ListenAndServe(s.SocketAddr, nil)
 	if err != nil {
@@ -87,85 +243,277 @@ func (s *Core) Run() {
 
 // 升级http为websocket协议
 func (s *Core) websocketUpgrade(w http.ResponseWriter, r *http.Request) {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	// 黑名单和封禁的 ip 不允许连接
+	if s.IpFilter.Denied(net.ParseIP(ip)) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if _, ok := s.Moderation.Banned(component.IpTarget(ip)); ok {
+		http.Error(w, "banned", http.StatusForbidden)
+		return
+	}
+	// 连接数限制，连接结束时释放
+	if code, ok := s.acquireConn(ip); !ok {
+		http.Error(w, "too many connections", code)
+		return
+	}
 	// 跨域
 	s.WebsocketUpgrade.CheckOrigin = func(r *http.Request) bool {
 		return true
 	}
+	// 支持 envelope 协议，没有协商子协议的按旧协议处理
+	s.WebsocketUpgrade.Subprotocols = []string{EnvelopeProtocol}
 	// 升级http为websocket
 	conn, err := s.WebsocketUpgrade.Upgrade(w, r, nil)
 
 	if err != nil {
 		log.Printf("http upgrade webcoket err %v", err)
+		s.releaseConn(ip)
 	} else {
+		// 断线重连带上之前的会话 token
+		token := r.URL.Query().Get("session")
+		// 房间名可以放在路径或者参数中
+		room := strings.TrimPrefix(r.URL.Path, "/ws")
+		room = strings.TrimPrefix(room, "/")
+		if room == "" {
+			room = r.URL.Query().Get("room")
+		}
+		// 管理员 token
+		auth := r.URL.Query().Get("auth")
 		SafeGo(func() {
-			s.listenWebsocket(conn)
+			defer s.releaseConn(ip)
+			s.listenWebsocket(conn, token, room, auth)
 		})
 	}
 }
 
 // 监听message消息
-func (s *Core) listenWebsocket(conn *websocket.Conn) {
-	defer func() {
-		err := conn.Close()
-		if err != nil {
-			log.Printf("close websocket err %v", err)
-		}
-	}()
+func (s *Core) listenWebsocket(conn *websocket.Conn, token string, room string, auth string) {
+	client := s.resume(conn, token)
+	resumed := client != nil
+	if !resumed {
+		client = NewClient(s, conn)
+		client.Room = roomName(room)
+		s.sessions.Store(client.Token, client)
+	}
+	// 下发服务端分配的 bot id 和会话 token
+	client.Welcome()
+	if resumed {
+		s.resync(client)
+	}
+	if auth != "" {
+		s.auth(client, auth)
+	}
+	// 心跳，收到 pong 延长读超时，超时没有收到的连接会在读消息时报错，走正常的断开流程
+	s.keepAlive(conn)
+	// 超过大小的消息 ReadMessage 会报错并断开连接
+	conn.SetReadLimit(s.Conf().ReadLimit)
 	// 监听
 	for {
-		// 尝试查询当前连接
-		cInfo, ok := s.Clients.Load(conn)
-		if !ok {
-			// 写入空
-			cInfo = &pb.BotStatusRequest{}
-		}
-		clientInfo, ok := cInfo.(*pb.BotStatusRequest)
-		if !ok {
-			log.Printf("assert sync map pb.BotStatusRequest err %v", clientInfo)
-			s.Clients.Delete(conn)
-			continue
-		}
+		clientInfo := client.Info
 		// 读取消息
 		_, message, err := conn.ReadMessage()
 		if err != nil {
 			log.Printf("read message error,client: %v break, ip: %v, err:%v", clientInfo.BotId, conn.RemoteAddr(), err)
-			messages <- &pb.BotStatusRequest{
-				BotId:   clientInfo.BotId,
-				Name:    clientInfo.Name,
-				Msg:     "我下线了~拜拜~",
-				PosInfo: clientInfo.PosInfo,
-			}
-			// 广播关闭连接
-			messages <- &pb.BotStatusRequest{
-				BotId:  clientInfo.BotId,
-				Status: pb.BotStatusRequest_close,
-			}
-			// 清除用户
-			s.Clients.Delete(conn)
-			// 关闭连接
-			err = conn.Close()
-			if err != nil {
-				log.Printf("close websocket err %v", err)
+			if e, ok := err.(net.Error); ok && e.Timeout() {
+				atomic.AddInt64(&s.reaped, 1)
 			}
+			s.disconnect(client, conn, err)
 			break
 		}
 		// 消息读取成功，解析消息
 		// 使用protobuf解析
-		pbr := &pb.BotStatusRequest{}
-		err = proto.Unmarshal(message, pbr)
+		env, err := decodeMessage(conn, message)
 		if err != nil {
+			if !s.allowEvent(client) {
+				continue
+			}
 			log.Printf("proto parse message %v err %v", message, err)
+			if e, ok := err.(*protocolError); ok {
+				client.Send(e.frame())
+			}
+			continue
+		}
+		// 按 payload 分发
+		var pbr *pb.BotStatusRequest
+		shout, greet := false, false
+		var ack *pb.ChatAck
+		switch payload := env.Payload.(type) {
+		case *pb.Envelope_Status:
+			pbr = payload.Status
+			// 不合法的状态不转发，旧协议没有错误消息，消息太长时用聊天气泡提示
+			if e := s.validateStatus(pbr); e != nil {
+				log.Printf("client %v invalid status %v", clientInfo.BotId, e)
+				client.Send(e.frame())
+				if e.field == "msg" && conn.Subprotocol() != EnvelopeProtocol {
+					client.Send(replyFrame(client.last, fmt.Sprintf("消息太长，最多 %d 个字", s.Conf().MaxMsgLen)))
+				}
+				continue
+			}
+			// 带聊天消息的按聊天限流
+			if pbr.Msg == "" && !s.allowStatus(client) {
+				continue
+			}
+			s.checkMove(client, pbr)
+			client.name = pbr.Name
+		case *pb.Envelope_Chat:
+			ack = &pb.ChatAck{ClientMsgId: payload.Chat.ClientMsgId}
+			if payload.Chat.Msg == "" {
+				ack.Result = pb.ChatAck_rejected
+				ack.Reason = "empty message"
+				client.Send(ackFrame(ack))
+				continue
+			}
+			if e := checkLen("msg", payload.Chat.Msg, s.Conf().MaxMsgLen); e != nil {
+				ack.Result = pb.ChatAck_rejected
+				ack.Reason = e.msg
+				client.Send(ackFrame(ack))
+				continue
+			}
+			// 只发聊天，沿用最近一次上报的状态，名称用未过滤的，下面统一过滤和转义
+			pbr = &pb.BotStatusRequest{}
+			if client.last != nil {
+				pbr = proto.Clone(client.last).(*pb.BotStatusRequest)
+			}
+			pbr.Name = client.name
+			pbr.Msg = payload.Chat.Msg
+			shout = payload.Chat.Shout
+		case *pb.Envelope_Direct:
+			if until, ok := s.muted(client); ok {
+				client.Send(errorFrame(pb.ProtocolError_muted, "muted "+until))
+				continue
+			}
+			if e := checkLen("msg", payload.Direct.GetMsg(), s.Conf().MaxMsgLen); e != nil {
+				client.Send(e.frame())
+				continue
+			}
+			if !s.allowChat(client) {
+				client.Send(errorFrame(pb.ProtocolError_rate_limited, "rate limited"))
+				continue
+			}
+			// 私聊同样过滤敏感词和html 标签，由广播协程投递
+			msg := html.EscapeString(s.TextSafer.Filter(payload.Direct.GetMsg()))
+			if msg == "" {
+				continue
+			}
+			messages <- &botMessage{client: client, direct: &pb.DirectMessage{
+				ToBotId:   payload.Direct.GetToBotId(),
+				FromBotId: clientInfo.BotId,
+				FromName:  clientInfo.Name,
+				Msg:       msg,
+			}}
+			continue
+		case *pb.Envelope_Typing:
+			if !s.allowEvent(client) {
+				continue
+			}
+			// 开始输入限制频率，停止输入只在转发过开始输入之后转发一次
+			if payload.Typing.Typing {
+				if time.Since(client.typingAt) < typingThrottle {
+					continue
+				}
+				client.typingAt = time.Now()
+			} else if !client.typing {
+				continue
+			}
+			client.typing = payload.Typing.Typing
+			messages <- &botMessage{client: client, typing: &pb.Typing{
+				BotId:  clientInfo.BotId,
+				Typing: payload.Typing.Typing,
+			}}
+			continue
+		case *pb.Envelope_Redeliver:
+			if !s.allowRedeliver(client) {
+				client.Send(errorFrame(pb.ProtocolError_rate_limited, "rate limited"))
+				continue
+			}
+			s.redeliver(client, payload.Redeliver)
+			continue
+		case *pb.Envelope_JoinRoom:
+			if !s.allowEvent(client) {
+				client.Send(errorFrame(pb.ProtocolError_rate_limited, "rate limited"))
+				continue
+			}
+			messages <- &botMessage{client: client, room: roomName(payload.JoinRoom.Room)}
 			continue
+		default:
+			if !s.allowEvent(client) {
+				continue
+			}
+			client.Send(errorFrame(pb.ProtocolError_unexpected_payload, fmt.Sprintf("unexpected payload %T", payload)))
+			continue
+		}
+		// 上线之后才能使用聊天命令，命令不作为聊天消息广播，// 开头的作为普通消息发出
+		if clientInfo.Status == pb.BotStatusRequest_connecting {
+			if client.nick != "" {
+				pbr.Name = client.nick
+			}
+			// 聊天限流，旧协议的消息去掉聊天内容，位置照常同步
+			if pbr.Msg != "" && !s.allowChat(client) {
+				if ack != nil {
+					ack.Result = pb.ChatAck_rate_limited
+					ack.Reason = "rate limited"
+					client.Send(ackFrame(ack))
+					continue
+				}
+				client.Send(replyFrame(client.last, "发言太快了，请稍后再试"))
+				pbr.Msg = ""
+			}
+			if isCommand(pbr.Msg) {
+				ctx, err := s.runCommand(client, pbr.Name, pbr.Msg)
+				if ctx.chat == "" {
+					if ack != nil {
+						if err != nil {
+							ack.Result = pb.ChatAck_rejected
+							ack.Reason = err.Error()
+						}
+						client.Send(ackFrame(ack))
+					}
+					continue
+				}
+				pbr.Msg, shout = ctx.chat, ctx.shout
+			} else if strings.HasPrefix(pbr.Msg, "//") {
+				pbr.Msg = pbr.Msg[1:]
+			}
+			// 禁言期间不能发聊天消息，位置照常同步
+			if pbr.Msg != "" {
+				if until, ok := s.muted(client); ok {
+					client.Send(replyFrame(client.last, "你已被禁言"+until))
+					if ack != nil {
+						ack.Result = pb.ChatAck_rejected
+						ack.Reason = "muted"
+						client.Send(ackFrame(ack))
+						continue
+					}
+					pbr.Msg = ""
+				}
+			}
+		}
+		if pbr.Msg != "" {
+			atomic.AddInt64(&client.chats, 1)
+		} else {
+			atomic.AddInt64(&client.statuses, 1)
 		}
+		// bot id 由服务端分配，不信任客户端上报的 id 和状态
+		pbr.BotId = clientInfo.BotId
+		pbr.Status = pb.BotStatusRequest_waiting
 		// 敏感词过滤
+		raw := pbr.Msg
 		pbr.Msg = s.TextSafer.Filter(pbr.Msg)
+		if ack != nil && pbr.Msg != raw {
+			ack.Result = pb.ChatAck_filtered
+		}
 		pbr.Name = s.TextSafer.Filter(pbr.Name)
 		// 过滤html 标签
 		pbr.Msg = html.EscapeString(pbr.Msg)
 		pbr.Name = html.EscapeString(pbr.Name)
 
-		// 如果是新用户初始化链接的ID
-		if clientInfo.BotId == "" {
+		// 如果是新用户初始化连接信息
+		if clientInfo.Status != pb.BotStatusRequest_connecting {
 			// 获取地理位置
 			posInfo := pb.PInfo{}
 			pinfo, err := s.IpSearch.Search(conn.RemoteAddr().String())
@@ -181,14 +529,25 @@ func (s *Core) listenWebsocket(conn *websocket.Conn) {
 					Isp:      pinfo.ISP,
 				}
 			}
-			s.Clients.Store(conn, &pb.BotStatusRequest{
-				BotId:   pbr.GetBotId(),
+			// 其他协程会读取 Info，加锁替换
+			client.lock.Lock()
+			client.Info = &pb.BotStatusRequest{
+				BotId:   clientInfo.BotId,
 				Name:    pbr.GetName(),
 				Status:  pb.BotStatusRequest_connecting,
 				PosInfo: &posInfo,
-			})
-			// 新用户进行上线提示
+			}
+			client.lock.Unlock()
+			s.Clients.Store(clientInfo.BotId, client)
+			// 新用户进行上线提示，上线前发的聊天消息不发送
+			if ack != nil {
+				ack.Result = pb.ChatAck_rejected
+				ack.Reason = "not online"
+				client.Send(ackFrame(ack))
+				ack = nil
+			}
 			pbr.Msg = "我上线啦~大家好呀"
+			greet = true
 			pbr.PosInfo = &posInfo
 			// 新用户上线，记录次数
 			s.loginChart.Entry()
@@ -196,49 +555,252 @@ func (s *Core) listenWebsocket(conn *websocket.Conn) {
 			// 老用户直接从clients获取pos信息
 			pbr.PosInfo = clientInfo.PosInfo
 		}
+		client.last = proto.Clone(pbr).(*pb.BotStatusRequest)
+		client.last.Msg = ""
 		// 广播队列
-		messages <- pbr
+		messages <- &botMessage{client: client, status: pbr, shout: shout, greet: greet, ack: ack}
+	}
+}
+
+// 设置读超时，收到 pong 后延长
+func (s *Core) keepAlive(conn *websocket.Conn) {
+	if s.PongWait <= 0 {
+		return
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(s.PongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(s.PongWait))
+	})
+}
+
+// 用会话 token 找回断线前的客户端
+func (s *Core) resume(conn *websocket.Conn, token string) *Client {
+	if token == "" {
+		return nil
+	}
+	v, ok := s.sessions.Load(token)
+	if !ok {
+		return nil
+	}
+	client, ok := v.(*Client)
+	if !ok {
+		log.Printf("assert sync map Client err %v", v)
+		return nil
+	}
+	if !client.Resume(conn) {
+		return nil
+	}
+	log.Printf("session resume, client: %v, ip: %v", client.BotId(), conn.RemoteAddr())
+
+	return client
+}
+
+// 重连后由广播协程重新下发视野内所有 bot 的快照，断线期间错过的进出视野以快照为准
+func (s *Core) resync(client *Client) {
+	messages <- &botMessage{client: client, call: func() {
+		room, ok := s.clientRoom[client]
+		if !ok || room.grid.Status(client) == nil {
+			return
+		}
+		s.send([]*Client{client}, false, room.grid.Visible(client)...)
+	}}
+}
+
+// 连接断开
+// 客户端主动关闭或者没有开启断线重连，直接下线；否则保留会话，宽限期内没有重连再下线
+func (s *Core) disconnect(client *Client, conn *websocket.Conn, err error) {
+	gen, ok := client.Detach(conn)
+	if !ok {
+		// 已经重连，换了新连接
+		return
+	}
+	if client.Closed() || s.SessionGrace <= 0 || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		client.Close()
+		s.leave(client)
+		return
+	}
+	time.AfterFunc(s.SessionGrace, func() {
+		if client.Expire(gen) {
+			s.leave(client)
+		}
+	})
+}
+
+// 会话结束，清除用户，由广播协程发送下线提示
+func (s *Core) leave(client *Client) {
+	botId := client.BotId()
+	s.Clients.Delete(botId)
+	s.sessions.Delete(client.Token)
+	messages <- &botMessage{
+		client: client,
+		status: closeStatus(botId),
 	}
 }
 
 // 广播
+// 位置同步按 tick 合并后只发给视野内的连接，聊天、上下线立即发送，都只在房间内广播
+// 房间只在广播协程中创建和删除
 func (s *Core) broadcast() {
-	// 始终读取messages
-	for msg := range messages {
-		if msg.Msg != "" {
-			log.Printf("%s : %s", msg.BotId+":"+msg.Name, msg.Msg)
+	ticker := time.NewTicker(time.Second / time.Duration(s.TickRate))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, room := range s.rooms {
+				s.expireTyping(room, now)
+				s.flush(room)
+			}
+		case m := <-messages:
+			if m.room != "" {
+				s.switchRoom(m.client, m.room)
+				continue
+			}
+			if m.direct != nil {
+				s.sendDirect(m.client, m.direct)
+				continue
+			}
+			if m.typing != nil {
+				s.typing(m.client, m.typing.Typing)
+				continue
+			}
+			if m.call != nil {
+				m.call()
+				continue
+			}
+			s.handleMessage(m)
 		}
+	}
+}
 
-		// 读取到之后进行广播，启动协程，是为了立即处理下一条msg
-		go func(m *pb.BotStatusRequest) {
-			// 遍历所有客户
-			s.Clients.Range(func(connKey, bs interface{}) bool {
+// 处理一条广播消息
+func (s *Core) handleMessage(m *botMessage) {
+	msg := m.status
+	room, ok := s.clientRoom[m.client]
 
-				resp := &pb.BotStatusResponse{
-					BotStatus: []*pb.BotStatusRequest{m},
-				}
-				b, err := proto.Marshal(resp)
-				if err != nil {
-					log.Printf("proto marshal error %v %+v", err, resp)
-					return true
-				}
+	// 下线，视野内的连接删除该 bot
+	if msg.Status == pb.BotStatusRequest_close {
+		if !ok {
+			return
+		}
+		if last := room.grid.Status(m.client); last != nil {
+			bye := proto.Clone(last).(*pb.BotStatusRequest)
+			bye.Msg = "我下线了~拜拜~"
+			s.sendChat(room, m.client, bye, false, false)
+		}
+		s.send(room.grid.Remove(m.client), false, msg)
+		s.leaveRoom(m.client)
+		return
+	}
 
-				// 二进制发送
-				conn, ok := connKey.(*websocket.Conn)
-				if !ok {
-					log.Printf("assert connkey websocket.Conn err %v", conn)
-					return true
-				}
-				// 防止并发写
-				s.ConnMutex.Lock()
-				err = conn.WriteMessage(websocket.BinaryMessage, b)
-				s.ConnMutex.Unlock()
-				if err != nil {
-					log.Printf("conn write message err %v", err)
+	// 第一条消息，加入连接时请求的房间
+	if !ok {
+		room = s.joinRoom(m.client, m.client.Room)
+	}
+	grid := room.grid
+	pending := room.pending
+
+	joined := grid.Status(m.client) == nil
+	entered, left := grid.Move(m.client, msg)
+
+	// 新连接，立即下发视野内所有 bot 的快照，不用等对方移动
+	// 聊天记录先发，旧协议回放用的下线状态不会删掉视野内的 bot
+	if joined {
+		s.replay(room, m.client)
+		s.send([]*Client{m.client}, false, grid.Visible(m.client)...)
+		entered = nil
+	}
+
+	// 离开视野，双方互相删除
+	leftStatus := []*pb.BotStatusRequest{}
+	for _, c := range left {
+		s.send([]*Client{c}, false, closeStatus(msg.BotId))
+		if st := grid.Status(c); st != nil {
+			leftStatus = append(leftStatus, closeStatus(st.BotId))
+		}
+	}
+	s.send([]*Client{m.client}, false, leftStatus...)
+
+	// 进入视野，下发对方的最新状态；自己的状态随下一个 tick 发给对方
+	enteredStatus := []*pb.BotStatusRequest{}
+	for _, c := range entered {
+		if st := grid.Status(c); st != nil {
+			enteredStatus = append(enteredStatus, st)
+		}
+	}
+	s.send([]*Client{m.client}, false, enteredStatus...)
+
+	if msg.Msg != "" {
+		// 发出消息就不再是输入中
+		s.stopTyping(room, m.client)
+		chat := s.sendChat(room, m.client, msg, m.shout, !m.greet)
+		if m.ack != nil {
+			m.ack.Id = chat.Id
+			m.ack.Time = chat.Time
+			m.client.Send(ackFrame(m.ack))
+		}
+		// envelope 协议的聊天消息不带状态，位置照常同步
+		pending[m.client] = grid.Status(m.client)
+	} else {
+		pending[m.client] = msg
+	}
+}
+
+// 关闭状态，客户端收到后删除该 bot
+func closeStatus(botId string) *pb.BotStatusRequest {
+	return &pb.BotStatusRequest{
+		BotId:  botId,
+		Status: pb.BotStatusRequest_close,
+	}
+}
+
+// 私聊只发给对方，同时回显给自己，对方不在线时告诉发送者
+// 旧协议用带 msg 的发送者状态表示，只有双方在同一个房间时才能这样显示，否则对方看到的会是一个不在房间里的 bot
+func (s *Core) sendDirect(from *Client, dm *pb.DirectMessage) {
+	v, ok := s.Clients.Load(dm.ToBotId)
+	to, _ := v.(*Client)
+	if !ok || to == nil || to.Closed() {
+		from.Send(errorFrame(pb.ProtocolError_target_offline, "target offline: "+dm.ToBotId))
+		return
+	}
+	// 私聊内容不写日志
+	log.Printf("[direct] %s -> %s", dm.FromBotId, dm.ToBotId)
+
+	var status *pb.BotStatusRequest
+	far := false
+	fromRoom, ok := s.clientRoom[from]
+	if ok && fromRoom == s.clientRoom[to] {
+		if st := fromRoom.grid.Status(from); st != nil {
+			status = proto.Clone(st).(*pb.BotStatusRequest)
+			status.Msg = "[私聊] " + dm.Msg
+			// 对方看不到发送者时，旧协议收到状态后要马上移除
+			far = true
+			for _, c := range fromRoom.grid.Viewers(from, true) {
+				if c == to {
+					far = false
+					break
 				}
-				return true
-			})
-		}(msg)
+			}
+		}
+	}
+	to.Send(directFrame(dm, status, far))
+	if to != from {
+		from.Send(directFrame(dm, status, false))
+	}
+}
+
+// 发送状态给指定的客户端，droppable 表示慢连接可以丢弃这条消息
+func (s *Core) send(clients []*Client, droppable bool, status ...*pb.BotStatusRequest) {
+	if len(clients) == 0 || len(status) == 0 {
+		return
+	}
+	s.sendFrame(clients, statusFrame(droppable, status...))
+}
+
+// 发送消息给指定的客户端，只放入各自的发送队列，不会被慢连接阻塞
+func (s *Core) sendFrame(clients []*Client, f *frame) {
+	for _, c := range clients {
+		c.Send(f)
 	}
 }
 
@@ -276,3 +838,38 @@ func (s *Core) ChartDataApi(w http.ResponseWriter, r *http.Request) {
 		log.Printf("ChartDataApi write %v", err)
 	}
 }
+
+type StatsApiRsp struct {
+	Online   int   `json:"online"`   // 在线人数
+	Sessions int   `json:"sessions"` // 会话数，包含断线等待重连的
+	Conns    int   `json:"conns"`    // websocket 连接数，包含还没有上线的
+	Reaped   int64 `json:"reaped"`   // 心跳超时被清理的连接数
+}
+
+// StatsApi 连接状态，用于监控
+func (s *Core) StatsApi(w http.ResponseWriter, r *http.Request) {
+	data := &StatsApiRsp{
+		Reaped: atomic.LoadInt64(&s.reaped),
+		Conns:  s.connCount(),
+	}
+	s.Clients.Range(func(_, _ interface{}) bool {
+		data.Online++
+		return true
+	})
+	s.sessions.Range(func(_, _ interface{}) bool {
+		data.Sessions++
+		return true
+	})
+
+	d, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("StatsApi marsharl %v", err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	_, err = w.Write(d)
+	if err != nil {
+		log.Printf("StatsApi write %v", err)
+	}
+}
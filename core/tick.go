@@ -0,0 +1,43 @@
+package core
+
+import (
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 下发一个 tick 内积累的位置同步
+// 同一个格子里的位置同步合并成一条消息，只编码一次，视野内多个格子的编码结果直接拼接，就是发给该客户端的一条消息
+func (s *Core) flush(room *Room) {
+	grid, pending := room.grid, room.pending
+	if len(pending) == 0 {
+		return
+	}
+
+	// 按格子合并
+	batches := map[cell][]*pb.BotStatusRequest{}
+	for c, st := range pending {
+		delete(pending, c)
+		e, ok := grid.entities[c]
+		if !ok {
+			continue
+		}
+		batches[e.cell] = append(batches[e.cell], st)
+	}
+
+	// 每个格子编码一次，分发给能看到这个格子的客户端
+	frames := map[*Client][]*frame{}
+	for cl, batch := range batches {
+		f := statusFrame(true, batch...)
+		if f == nil {
+			continue
+		}
+		for _, around := range cl.around() {
+			for _, c := range grid.members(around) {
+				frames[c] = append(frames[c], f)
+			}
+		}
+	}
+
+	for c, f := range frames {
+		c.Send(joinFrames(f))
+	}
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"time"
+)
+
+const (
+	// 开始输入没有后续时，超过这个时间自动停止
+	typingTimeout = 5 * time.Second
+	// 同一个客户端开始输入的最小间隔，超过频率的直接丢弃
+	typingThrottle = time.Second
+)
+
+// 正在输入，转发给视野内的其他客户
+// 只在状态变化时转发，输入中重复的开始输入只延长过期时间
+func (s *Core) typing(client *Client, typing bool) {
+	room, ok := s.clientRoom[client]
+	if !ok || room.grid.Status(client) == nil {
+		return
+	}
+	if !typing {
+		s.stopTyping(room, client)
+		return
+	}
+
+	_, already := room.typing[client]
+	room.typing[client] = time.Now().Add(typingTimeout)
+	if !already {
+		s.sendFrame(room.grid.Viewers(client, false), typingFrame(client.BotId(), true))
+	}
+}
+
+// 停止输入
+func (s *Core) stopTyping(room *Room, client *Client) {
+	if _, ok := room.typing[client]; !ok {
+		return
+	}
+	delete(room.typing, client)
+	s.sendFrame(room.grid.Viewers(client, false), typingFrame(client.BotId(), false))
+}
+
+// 超时没有收到停止输入的，自动停止
+func (s *Core) expireTyping(room *Room, now time.Time) {
+	for client, expire := range room.typing {
+		if now.After(expire) {
+			s.stopTyping(room, client)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	pb "github.com/sunshinev/go-space-chat/proto/star"
+)
+
+// 前端上报的瞳孔是画布上的坐标，眼眶中心在 (x+eyeOffset, y+eyeOffset)，瞳孔不会超出眼眶半径 eyeRange
+const (
+	eyeOffset = 5
+	eyeRange  = 8
+)
+
+// 字段不合法的协议错误，field 是 proto 中的字段名
+func fieldError(code pb.ProtocolErrorCodeType, field string, format string, args ...interface{}) *protocolError {
+	return &protocolError{code: code, field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// 校验上报的状态，不合法的整条丢弃，不转发给其他人
+func (s *Core) validateStatus(pbr *pb.BotStatusRequest) *protocolError {
+	conf := s.Conf()
+	if _, ok := pb.BotStatusRequestStatusType_name[int32(pbr.Status)]; !ok {
+		return fieldError(pb.ProtocolError_invalid_field, "status", "unknown status %d", pbr.Status)
+	}
+	if _, ok := pb.BotStatusRequestGenderType_name[int32(pbr.Gender)]; !ok {
+		return fieldError(pb.ProtocolError_invalid_field, "gender", "unknown gender %d", pbr.Gender)
+	}
+	if err := checkLen("name", pbr.Name, conf.MaxNameLen); err != nil {
+		return err
+	}
+	if err := checkLen("msg", pbr.Msg, conf.MaxMsgLen); err != nil {
+		return err
+	}
+	coords := []struct {
+		field string
+		value float32
+	}{
+		{"x", pbr.X},
+		{"y", pbr.Y},
+		{"real_x", pbr.RealX},
+		{"real_y", pbr.RealY},
+	}
+	for _, c := range coords {
+		if err := checkCoord(c.field, c.value, conf.MaxCoord); err != nil {
+			return err
+		}
+	}
+	// 鼠标正好在眼睛上时前端会算出 NaN，按眼睛居中处理
+	if !finite(pbr.EyeX) {
+		pbr.EyeX = pbr.X + eyeOffset
+	}
+	if !finite(pbr.EyeY) {
+		pbr.EyeY = pbr.Y + eyeOffset
+	}
+	if err := checkCoord("eye_x", pbr.EyeX-pbr.X-eyeOffset, eyeRange); err != nil {
+		return err
+	}
+	return checkCoord("eye_y", pbr.EyeY-pbr.Y-eyeOffset, eyeRange)
+}
+
+// 字数不能超过 max
+func checkLen(field string, value string, max int) *protocolError {
+	if n := utf8.RuneCountInString(value); n > max {
+		return fieldError(pb.ProtocolError_too_long, field, "%s too long, %d > %d", field, n, max)
+	}
+	return nil
+}
+
+// 坐标必须是有限的数，绝对值不超过 max
+func checkCoord(field string, value float32, max float64) *protocolError {
+	if !finite(value) {
+		return fieldError(pb.ProtocolError_invalid_field, field, "%s is not finite", field)
+	}
+	if math.Abs(float64(value)) > max {
+		return fieldError(pb.ProtocolError_out_of_range, field, "%s out of range, |%v| > %v", field, value, max)
+	}
+	return nil
+}
+
+func finite(v float32) bool {
+	f := float64(v)
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
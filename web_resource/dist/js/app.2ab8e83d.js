@@ -0,0 +1,2 @@
+(function(t){function e(e){for(var r,a,i=e[0],u=e[1],l=e[2],p=0,c=[];p<i.length;p++)a=i[p],Object.prototype.hasOwnProperty.call(n,a)&&n[a]&&c.push(n[a][0]),n[a]=0;for(r in u)Object.prototype.hasOwnProperty.call(u,r)&&(t[r]=u[r]);d&&d(e);while(c.length)c.shift()();return s.push.apply(s,l||[]),o()}function o(){for(var t,e=0;e<s.length;e++){for(var o=s[e],r=!0,i=1;i<o.length;i++){var u=o[i];0!==n[u]&&(r=!1)}r&&(s.splice(e--,1),t=a(a.s=o[0]))}return t}var r={},n={app:0},s=[];function a(e){if(r[e])return r[e].exports;var o=r[e]={i:e,l:!1,exports:{}};return t[e].call(o.exports,o,o.exports,a),o.l=!0,o.exports}a.m=t,a.c=r,a.d=function(t,e,o){a.o(t,e)||Object.defineProperty(t,e,{enumerable:!0,get:o})},a.r=function(t){"undefined"!==typeof Symbol&&Symbol.toStringTag&&Object.defineProperty(t,Symbol.toStringTag,{value:"Module"}),Object.defineProperty(t,"__esModule",{value:!0})},a.t=function(t,e){if(1&e&&(t=a(t)),8&e)return t;if(4&e&&"object"===typeof t&&t&&t.__esModule)return t;var o=Object.create(null);if(a.r(o),Object.defineProperty(o,"default",{enumerable:!0,value:t}),2&e&&"string"!=typeof t)for(var r in t)a.d(o,r,function(e){return t[e]}.bind(null,r));return o},a.n=function(t){var e=t&&t.__esModule?function(){return t["default"]}:function(){return t};return a.d(e,"a",e),e},a.o=function(t,e){return Object.prototype.hasOwnProperty.call(t,e)},a.p="/";var i=window["webpackJsonp"]=window["webpackJsonp"]||[],u=i.push.bind(i);i.push=e,i=i.slice();for(var l=0;l<i.length;l++)e(i[l]);var d=u;s.push([0,"chunk-vendors"]),o()})({0:function(t,e,o){t.exports=o("56d7")},"034f":function(t,e,o){"use strict";var r=o("85ec"),n=o.n(r);n.a},4678:function(t,e,o){var r={"./af":"2bfb","./af.js":"2bfb","./ar":"8e73","./ar-dz":"a356","./ar-dz.js":"a356","./ar-kw":"423e","./ar-kw.js":"423e","./ar-ly":"1cfd","./ar-ly.js":"1cfd","./ar-ma":"0a84","./ar-ma.js":"0a84","./ar-sa":"8230","./ar-sa.js":"8230","./ar-tn":"6d83","./ar-tn.js":"6d83","./ar.js":"8e73","./az":"485c","./az.js":"485c","./be":"1fc1","./be.js":"1fc1","./bg":"84aa","./bg.js":"84aa","./bm":"a7fa","./bm.js":"a7fa","./bn":"9043","./bn-bd":"9686","./bn-bd.js":"9686","./bn.js":"9043","./bo":"d26a","./bo.js":"d26a","./br":"6887","./br.js":"6887","./bs":"2554","./bs.js":"2554","./ca":"d716","./ca.js":"d716","./cs":"3c0d","./cs.js":"3c0d","./cv":"03ec","./cv.js":"03ec","./cy":"9797","./cy.js":"9797","./da":"0f14","./da.js":"0f14","./de":"b469","./de-at":"b3eb","./de-at.js":"b3eb","./de-ch":"bb71","./de-ch.js":"bb71","./de.js":"b469","./dv":"598a","./dv.js":"598a","./el":"8d47","./el.js":"8d47","./en-au":"0e6b","./en-au.js":"0e6b","./en-ca":"3886","./en-ca.js":"3886","./en-gb":"39a6","./en-gb.js":"39a6","./en-ie":"e1d3","./en-ie.js":"e1d3","./en-il":"7333","./en-il.js":"7333","./en-in":"ec2e","./en-in.js":"ec2e","./en-nz":"6f50","./en-nz.js":"6f50","./en-sg":"b7e9","./en-sg.js":"b7e9","./eo":"65db","./eo.js":"65db","./es":"898b","./es-do":"0a3c","./es-do.js":"0a3c","./es-mx":"b5b7","./es-mx.js":"b5b7","./es-us":"55c9","./es-us.js":"55c9","./es.js":"898b","./et":"ec18","./et.js":"ec18","./eu":"0ff2","./eu.js":"0ff2","./fa":"8df4","./fa.js":"8df4","./fi":"81e9","./fi.js":"81e9","./fil":"d69a","./fil.js":"d69a","./fo":"0721","./fo.js":"0721","./fr":"9f26","./fr-ca":"d9f8","./fr-ca.js":"d9f8","./fr-ch":"0e49","./fr-ch.js":"0e49","./fr.js":"9f26","./fy":"7118","./fy.js":"7118","./ga":"5120","./ga.js":"5120","./gd":"f6b4","./gd.js":"f6b4","./gl":"8840","./gl.js":"8840","./gom-deva":"aaf2","./gom-deva.js":"aaf2","./gom-latn":"0caa","./gom-latn.js":"0caa","./gu":"e0c5","./gu.js":"e0c5","./he":"c7aa","./he.js":"c7aa","./hi":"dc4d","./hi.js":"dc4d","./hr":"4ba9","./hr.js":"4ba9","./hu":"5b14","./hu.js":"5b14","./hy-am":"d6b6","./hy-am.js":"d6b6","./id":"5038","./id.js":"5038","./is":"0558","./is.js":"0558","./it":"6e98","./it-ch":"6f12","./it-ch.js":"6f12","./it.js":"6e98","./ja":"079e","./ja.js":"079e","./jv":"b540","./jv.js":"b540","./ka":"201b","./ka.js":"201b","./kk":"6d79","./kk.js":"6d79","./km":"e81d","./km.js":"e81d","./kn":"3e92","./kn.js":"3e92","./ko":"22f8","./ko.js":"22f8","./ku":"2421","./ku.js":"2421","./ky":"9609","./ky.js":"9609","./lb":"440c","./lb.js":"440c","./lo":"b29d","./lo.js":"b29d","./lt":"26f9","./lt.js":"26f9","./lv":"b97c","./lv.js":"b97c","./me":"293c","./me.js":"293c","./mi":"688b","./mi.js":"688b","./mk":"6909","./mk.js":"6909","./ml":"02fb","./ml.js":"02fb","./mn":"958b","./mn.js":"958b","./mr":"39bd","./mr.js":"39bd","./ms":"ebe4","./ms-my":"6403","./ms-my.js":"6403","./ms.js":"ebe4","./mt":"1b45","./mt.js":"1b45","./my":"8689","./my.js":"8689","./nb":"6ce3","./nb.js":"6ce3","./ne":"3a39","./ne.js":"3a39","./nl":"facd","./nl-be":"db29","./nl-be.js":"db29","./nl.js":"facd","./nn":"b84c","./nn.js":"b84c","./oc-lnc":"167b","./oc-lnc.js":"167b","./pa-in":"f3ff","./pa-in.js":"f3ff","./pl":"8d57","./pl.js":"8d57","./pt":"f260","./pt-br":"d2d4","./pt-br.js":"d2d4","./pt.js":"f260","./ro":"972c","./ro.js":"972c","./ru":"957c","./ru.js":"957c","./sd":"6784","./sd.js":"6784","./se":"ffff","./se.js":"ffff","./si":"eda5","./si.js":"eda5","./sk":"7be6","./sk.js":"7be6","./sl":"8155","./sl.js":"8155","./sq":"c8f3","./sq.js":"c8f3","./sr":"cf1e","./sr-cyrl":"13e9","./sr-cyrl.js":"13e9","./sr.js":"cf1e","./ss":"52bd","./ss.js":"52bd","./sv":"5fbd","./sv.js":"5fbd","./sw":"74dc","./sw.js":"74dc","./ta":"3de5","./ta.js":"3de5","./te":"5cbb","./te.js":"5cbb","./tet":"576c","./tet.js":"576c","./tg":"3b1b","./tg.js":"3b1b","./th":"10e8","./th.js":"10e8","./tk":"5aff","./tk.js":"5aff","./tl-ph":"0f38","./tl-ph.js":"0f38","./tlh":"cf75","./tlh.js":"cf75","./tr":"0e81","./tr.js":"0e81","./tzl":"cf51","./tzl.js":"cf51","./tzm":"c109","./tzm-latn":"b53d","./tzm-latn.js":"b53d","./tzm.js":"c109","./ug-cn":"6117","./ug-cn.js":"6117","./uk":"ada2","./uk.js":"ada2","./ur":"5294","./ur.js":"5294","./uz":"2e8c","./uz-latn":"010e","./uz-latn.js":"010e","./uz.js":"2e8c","./vi":"2921","./vi.js":"2921","./x-pseudo":"fd7e","./x-pseudo.js":"fd7e","./yo":"7f33","./yo.js":"7f33","./zh-cn":"5c3a","./zh-cn.js":"5c3a","./zh-hk":"49ab","./zh-hk.js":"49ab","./zh-mo":"3a6c","./zh-mo.js":"3a6c","./zh-tw":"90ea","./zh-tw.js":"90ea"};function n(t){var e=s(t);return o(e)}function s(t){if(!o.o(r,t)){var e=new Error("Cannot find module '"+t+"'");throw e.code="MODULE_NOT_FOUND",e}return r[t]}n.keys=function(){return Object.keys(r)},n.resolve=s,t.exports=n,n.id="4678"},"56d7":function(t,e,o){"use strict";o.r(e);o("e260"),o("e6cf"),o("cca6"),o("a79d");var r=o("2b0e"),n=function(){var t=this,e=t.$createElement,o=t._self._c||e;return o("div",{attrs:{id:"app"}},[o("Donghua"),o("LoginChart")],1)},s=[],a=function(){var t=this,e=t.$createElement,o=t._self._c||e;return o("canvas",{attrs:{id:"test"}})},i=[],u=(o("cb29"),o("b0c0"),o("d3b7"),o("25f0"),o("498a"),o("8513"),o("9d58"),o("6111"),null),l=null,d=[],p=200,c={x:0,y:0,z:10},f=3,g={up:87,down:83,left:65,right:68,talk:32,send:13},b={x:0,y:0},h={x:{min:0,max:2e3},y:{min:0,max:2e3},z:{min:0,max:10}},y=2,m={up:!1,down:!1,left:!1,right:!1},x=[.5,0,0],j=!0,v=0,S=0,w={x:0,y:0},M={x:0,y:0},R=null,F=!1,k=null,I=null,z={x:0,y:0,e_x:0,e_y:0,r_x:0,r_y:0,bot_id:"",name:"",gender:0},_={},E=!1,q={x:0,y:0},P={},W={},B=document.createElement("div");FPSMeter.theme.dark.count.fontSize="12px";var C=new FPSMeter;function O(){l=document.getElementById("test"),l.width=window.innerWidth,l.height=window.innerHeight,u=l.getContext("2d"),u.shadowColor="white",u.shadowBlur=10,b={x:l.width/2,y:l.height/2},c.x=l.width/2,c.y=l.height/2,h.x.max=l.width,h.y.max=l.height,d=L(),z.bot_id=Math.random().toString(36).substr(2),A()}function D(){u.clearRect(0,0,l.width,l.height),u.fillStyle="rgb(20,7,34)",u.fillRect(0,0,l.width,l.height),T(),d=N(d,x),$(u,d),ut(),z.x=c.x,z.y=c.y,ft(),ot(),C.tick(),window.requestAnimationFrame(D)}function A(){setInterval((function(){j&&(x=[Math.random()-.5,Math.random()-.5,0])}),5e3)}function T(){v&&(x=[Math.random()-.5,Math.random()-.5,0],v=0)}function L(){for(var t=h.x.max-h.x.min,e=h.y.max-h.y.min,o=[],r=0;r<p;r++){var n=G(w.x+t,w.x),s=G(w.y+e,w.y),a=G(h.z.max,h.z.min),i=X(a),u=Y(a);o.push({x:n,y:s,z:a,c:i,s:u})}return o.sort((function(t,e){return t.z-e.z})),o}function N(t){var e=arguments.length>1&&void 0!==arguments[1]?arguments[1]:[0,0,0];for(var o in t){var r=t[o];r.x+=e[0],r.y+=e[1],r.z+=e[2],r.x>l.width?r.x-=l.width:r.x<0&&(r.x+=l.width),r.y>l.height?r.y-=l.height:r.y<0&&(r.y+=l.height),t[o]=r}return t}function G(t){var e=arguments.length>1&&void 0!==arguments[1]?arguments[1]:0;return Math.floor(Math.random()*(t-e))+e}function X(t){var e=Math.floor(155*t/(h.z.max-h.z.min))+100;return"rgb("+e+","+e+","+e+")"}function Y(t){return t*f/c.z}function $(t){var e=arguments.length>1&&void 0!==arguments[1]?arguments[1]:[];for(var o in e){var r=H(e[o].x,e[o].y,e[o].z,e[o].c,e[o].s);(r.x<l.width||r.y<l.height)&&J(t,r.x,r.y,r.c,r.s)}}function H(t,e,o,r,n){var s={x:(t-c.x)*c.z/(c.z-o)+c.x,y:(e-c.y)*c.z/(c.z-o)+c.y,c:r,s:n};return s}function J(t,e,o,r,n){t.beginPath(),t.arc(e,o,n,0,2*Math.PI),t.fillStyle=r,t.fill()}function U(){var t=arguments.length>0&&void 0!==arguments[0]?arguments[0]:l.width/2,e=arguments.length>1&&void 0!==arguments[1]?arguments[1]:l.height/2;u.beginPath(),t+=5,e+=5,u.arc(t,e,8,0,2*Math.PI),z.gender===proto.botStatusRequest.gender_type.WOMAN?u.fillStyle="rgb(255,20,147)":u.fillStyle="rgb(0,191,255)",u.fill(),u.beginPath();var o=K(t,e,8);z.e_x=o[0],z.e_y=o[1],u.arc(o[0],o[1],4,0,2*Math.PI),u.fillStyle="rgb(255,255,255)",u.fill(),u.font="14px Arial",z.gender===proto.botStatusRequest.gender_type.WOMAN?u.fillStyle="rgb(255,20,147)":u.fillStyle="rgb(0,191,255)",u.fillText(z.name,t-8,e+20)}function V(){var t=arguments.length>0&&void 0!==arguments[0]?arguments[0]:l.width/2,e=arguments.length>1&&void 0!==arguments[1]?arguments[1]:l.height/2,o=arguments.length>2?arguments[2]:void 0,r=arguments.length>3?arguments[3]:void 0,n=arguments.length>4?arguments[4]:void 0,s=arguments.length>5?arguments[5]:void 0,a=arguments.length>6?arguments[6]:void 0;u.beginPath(),t+=5,e+=5,u.arc(t,e,8,0,2*Math.PI),s===proto.botStatusRequest.gender_type.WOMAN?u.fillStyle="rgb(255,20,147)":u.fillStyle="rgb(0,191,255)",u.fill(),u.beginPath(),u.arc(o,r,4,0,2*Math.PI),u.fillStyle="rgb(255,255,255)",u.fill(),u.font="14px Arial",s===proto.botStatusRequest.gender_type.WOMAN?u.fillStyle="rgb(255,20,147)":u.fillStyle="rgb(0,191,255)",u.fillText(n,t-8,e+20),u.fillText(a,t-8,e+40)}function K(t,e,o){var r=5,n=(t-M.x)*(o-r)/Math.sqrt(Math.pow(t-M.x,2)+Math.pow(e-M.y,2)),s=(e-M.y)*(o-r)/Math.sqrt(Math.pow(t-M.x,2)+Math.pow(e-M.y,2));m.up?(n=0,s=o-r):m.down&&(n=0,s=-(o-r)),m.left?(n=o-r,s=0):m.right&&(n=-(o-r),s=0);var a=Math.sqrt(Math.pow(o-r,2)/2);return m.up&&m.left?(n=a,s=a):m.up&&m.right?(n=-a,s=a):m.down&&m.left?(n=a,s=-a):m.down&&m.right&&(n=-a,s=-a),[t-n,e-s]}function Q(){window.addEventListener("keydown",(function(t){if(F&&t.keyCode!=g.send)return!1;switch(t.keyCode){case g.up:m.up=!0,m.down=!1;break;case g.down:m.up=!1,m.down=!0;break;case g.right:m.right=!0,m.left=!1;break;case g.left:m.left=!0,m.right=!1;break;case g.talk:Z();break;case g.send:tt();break}})),window.addEventListener("keyup",(function(t){switch(t.keyCode){case g.up:m.up=!1;break;case g.down:m.down=!1;break;case g.right:m.right=!1;break;case g.left:m.left=!1;break}t.keyCode!=g.up&&t.keyCode!=g.down&&t.keyCode!=g.left&&t.keyCode!=g.right||(j=!0,v=1)})),window.addEventListener("mousemove",(function(t){M={x:t.x,y:t.y}})),document.body.addEventListener("touchmove",(function(t){t.preventDefault()}))}function Z(){if(null!=R)return!1;F=!0,R=document.createElement("input"),R.setAttribute("style","position:fixed;left:"+c.x+"px;top:"+(c.y+30)+"px;background-color:rgba(200,200,200,0.2);border:1px solid rgba(200,200,200,0.2);border-radius:10px;padding:5px;outline:none;width:150px;color:white;font-size:12px"),R.setAttribute("maxlength",50),document.body.appendChild(R),R.addEventListener("focus",(function(){})),R.addEventListener("blur",(function(){document.body.removeChild(R),R=null,F=!1})),R.focus()}function tt(){if(!R||!R.value)return!1;var t=R.value;R.blur(),ft(t)}function et(t){var e=document.createElement("p");e.innerHTML="<span style='padding:0 5px;margin:5px 0;display:inline-block;background-color:rgba(200,200,200,0.2);border:1px solid rgba(200,200,200,0.2);border-radius:10px;'>"+t+"</span>",k.appendChild(e),setTimeout((function(){k.removeChild(e)}),8e3)}function ot(){for(var t in P)t===z.bot_id?(null==k&&(k=document.createElement("div"),k.setAttribute("style","position:fixed;left:"+c.x+"px;bottom:"+(l.height-c.y+20)+"px;color:white;font-size:12px"),document.body.appendChild(k)),P[t].msg&&(et(P[t].msg),P[t].msg="")):t!==z.bot_id&&rt(P[t].r_x+P[t].x-q.x,P[t].r_y+P[t].y-q.y)&&(V(P[t].r_x+P[t].x-q.x,P[t].r_y+P[t].y-q.y,P[t].r_x+P[t].e_x-q.x,P[t].r_y+P[t].e_y-q.y,P[t].name,P[t].gender,P[t].pos_info.getCity()),nt(t),P[t].msg&&(st(t,P[t].msg),P[t].msg=""),at(t))}function rt(t,e){return!(t<0)&&(!(e<0)&&(!(t>l.width)&&!(e>l.height)))}function nt(t){W[t]||(W[t]=document.createElement("div"),W[t].setAttribute("style","position:fixed;left:"+(P[t].x+P[t].r_x-q.x)+"px;bottom:"+(l.height-(P[t].y+P[t].r_y-q.y)+20)+"px;color:white;font-size:12px"),document.body.appendChild(W[t]))}function st(t,e){P[t];var o=W[t],r=document.createElement("p");r.innerHTML="<span style='padding:0 5px;margin:5px 0;display:inline-block;background-color:rgba(200,200,200,0.2);border:1px solid rgba(200,200,200,0.2);border-radius:10px;'>"+e+"</span>",o.appendChild(r),setTimeout((function(){o.removeChild(r)}),15e3)}function at(t){var e=P[t],o=W[t];o.setAttribute("style","position:fixed;left:"+(e.x+e.r_x-q.x)+"px;bottom:"+(l.height-(e.y+e.r_y-q.y)+20)+"px;color:white;font-size:12px")}function it(){null!=k&&k.setAttribute("style","position:fixed;left:"+c.x+"px;bottom:"+(l.height-c.y+20)+"px;color:white;font-size:12px")}function ut(){var t=b.y,e=b.x,o=0,r=0;if(m.up?(t=b.y-y,r=-y):m.down&&(t=b.y+y,r=y),m.left?(e=b.x-y,o=-y):m.right&&(e=b.x+y,o=y),o||r){lt("far");var n=100;dt(e,t,l.width/2,l.height/2)>=n?(j=!1,v=0,x=[-o,-r,0],q.x+=o,q.y+=r,z.r_x=q.x,z.r_y=q.y):(b.y=t,b.x=e)}else lt("near");c.x=b.x,c.y=b.y,it(),U(b.x,b.y)}function lt(){var t=arguments.length>0&&void 0!==arguments[0]?arguments[0]:"far",e=.1,o=5,r=1;"far"==t?S<Math.PI&&(S+=e,c.z=c.z+r*Math.sin(S)/o):"near"==t&&S>0&&(S-=e,c.z=c.z-r*Math.sin(S)/o)}function dt(t,e,o,r){return Math.sqrt(Math.pow(t-o,2)+Math.pow(e-r,2))}function pt(){I=new WebSocket("ws://"+location.hostname+":9000/ws"),I.binaryType="arraybuffer",I.onopen=function(){console.info("ws open"),E=!0},I.onmessage=function(t){var e=proto.botStatusResponse.deserializeBinary(t.data);e.hasWelcome()&&(z.bot_id=e.getWelcome().getBotId());var o=e.getBotStatusList();for(var r in o)o[r].getStatus()!==proto.botStatusRequest.status_type.CLOSE?(P[o[r].getBotId()]={x:o[r].getX(),y:o[r].getY(),e_x:o[r].getEyeX(),e_y:o[r].getEyeY(),r_x:o[r].getRealX(),r_y:o[r].getRealY(),msg:o[r].getMsg(),name:o[r].getName(),gender:o[r].getGender(),pos_info:o[r].getPosInfo()},xt(P[o[r].getBotId()])):delete P[o[r].getBotId()]},I.onclose=function(){console.info("ws close")}}var ct=!0;function ft(){var t=arguments.length>0&&void 0!==arguments[0]?arguments[0]:"";if(!E)return!1;var e=!1;if(_)for(var o in z)z[o]!==_[o]&&(e=!0);else e=!0;if(""===t){if(!ct)return;ct=!1,setTimeout((function(){ct=!0}),30)}if(e||t){var r=new proto.botStatusRequest;r.setBotId(z.bot_id),r.setX(c.x),r.setY(c.y),r.setEyeX(z.e_x),r.setEyeY(z.e_y),r.setRealX(q.x),r.setRealY(q.y),r.setMsg(t),r.setName(z.name),r.setGender(z.gender),I.send(r.serializeBinary()),Object.assign(_,z)}}function gt(){var t=localStorage.getItem("star_name");z.name=null!==t&&""!==t?t:"Guest"+Math.random().toString(36).substr(2);var e=localStorage.getItem("star_gender");z.gender=null!==e?parseInt(e):proto.botStatusRequest.gender_type.MAN}function bt(){var t=document.createElement("div");t.setAttribute("style","position:fixed;text-align:center;left:5px;top:50px;width:30px;height:200px;background-color:rgba(0,0,0,0.5);border:1px solid rgba(0,0,0,0.5);border-radius:5px;"),document.body.appendChild(t);var e=ht(t,"image/human.png","点我修改昵称"),o=null;e.addEventListener("click",(function(t){if(o)return!1;o=document.createElement("input"),o.setAttribute("style","position:fixed;left:50px;top:50px;background-color:white;border:1px solid white;border-radius:5px;padding:5px;outline:none;width:150px;font-size:12px"),o.setAttribute("placeholder","请输入昵称，长度10"),o.setAttribute("maxlength",10),document.body.appendChild(o),o.focus(),o.addEventListener("blur",(function(){""!==o.value&&(z.name=o.value,localStorage.setItem("star_name",z.name)),document.body.removeChild(o),o=null}))}));var r=ht(t,"image/m.png","男生");r.addEventListener("click",(function(t){z.gender=proto.botStatusRequest.gender_type.MAN,localStorage.setItem("star_gender",z.gender)}));var n=ht(t,"image/w.png","女生");n.addEventListener("click",(function(t){z.gender=proto.botStatusRequest.gender_type.WOMAN,localStorage.setItem("star_gender",z.gender)}))}function ht(t,e){var o=arguments.length>2&&void 0!==arguments[2]?arguments[2]:"",r=document.createElement("img");return r.setAttribute("style","width:25px;height:25px;border:1px solid rgba(200,200,0,0.5);color:white;cursor:default;border-radius:5px;"),r.setAttribute("src",e),r.setAttribute("title",o),t.appendChild(r),r}function yt(){B.setAttribute("style","position:fixed;right:5px;bottom:200px;width:400px;height:70%;color:rgba(200,200,200,0.8);border:1px solid rgba(200,200,200,0.8);cursor:default;overflow-y:auto;border-radius:5px;"),document.body.appendChild(B)}function mt(t,e){if(""!==e.trim()){var o=document.createElement("div");o.setAttribute("style","margin:2px;"),o.innerHTML="<div><span style='color: darkred'>"+t+"：</span>"+e,B.appendChild(o),B.scrollTop=B.scrollHeight}}function xt(t){if(""!==t.msg.trim()){var e=document.createElement("div");e.setAttribute("style","margin:2px;"),e.innerHTML="<div><span style='color: lightseagreen'>["+t.pos_info.getCity()+t.pos_info.getIsp()+"]</span><span style='color: darkgreen'>@"+t.name+"：</span>"+t.msg,B.appendChild(e),B.scrollTop=B.scrollHeight}}function jt(){var t=["欢迎来到这个秘密的地方，茫茫人海，如果能在这里相遇，说明是一种缘分~","互动方式如下：","1. W A S D进行上下左右","2. 空格开启聊天框，回车发送消息","3. 左上角修改昵称、性别，点击空白修改成功","4. 新增用户上线频率全天分布图","git 地址：<a href='https://github.com/sunshinev/go-space-chat' target='_blank'>https://github.com/sunshinev/go-space-chat</a>","前端 Vue+canvas+websocket+protobuf，后端 Golang+websocket+protobuf+goroutine"];for(var e in t)mt("管理员",t[e])}var vt=function(){yt(),jt(),O(),Q(),bt(),gt(),pt(),window.requestAnimationFrame(D)},St={data:function(){return{}},methods:{},mounted:function(){vt()}},wt=St,Mt=o("2877"),Rt=Object(Mt["a"])(wt,a,i,!1,null,null,null),Ft=Rt.exports,kt=function(){var t=this,e=t.$createElement;t._self._c;return t._m(0)},It=[function(){var t=this,e=t.$createElement,o=t._self._c||e;return o("div",{staticStyle:{width:"100%",height:"256px",position:"absolute",bottom:"0","z-index":"10"},attrs:{height:"200"}},[o("canvas",{attrs:{id:"myChart"}})])}],zt=o("30ef"),_t=o.n(zt),Et=o("bc3a"),qt=o.n(Et),Pt={data:function(){return{xlist:[],ylist:[]}},methods:{getChartData:function(){var t=this;qt.a.get("/login_charts").then((function(e){t.xlist=e.data.x,t.ylist=e.data.y,t.renderCharts()}))},renderCharts:function(){var t=document.getElementById("myChart"),e={maintainAspectRatio:!1,spanGaps:!1,elements:{line:{tension:.4}},plugins:{filler:{propagate:!1}},scales:{xAxes:[{ticks:{autoSkip:!0,maxRotation:0,display:!0}}]}};new _t.a(t,{type:"line",data:{labels:this.xlist,datasets:[{backgroundColor:"rgba(255, 99, 132, 0.5)",borderColor:"rgba(255, 99, 132, 0.5)",data:this.ylist,label:"",fill:"start"}]},options:_t.a.helpers.merge(e,{title:{text:"用户上线全天时间分布图",display:!0,position:"bottom"}})})}},beforeMount:function(){},mounted:function(){this.getChartData()}},Wt=Pt,Bt=Object(Mt["a"])(Wt,kt,It,!1,null,null,null),Ct=Bt.exports,Ot={name:"App",components:{Donghua:Ft,LoginChart:Ct}},Dt=Ot,At=(o("034f"),Object(Mt["a"])(Dt,n,s,!1,null,null,null)),Tt=At.exports;new r["a"]({render:function(t){return t(Tt)}}).$mount("#app")},"85ec":function(t,e,o){},"9d58":function(t,e,o){var r=o("8513"),n=r,s=Function("return this")();n.exportSymbol("proto.botStatusRequest",null,s),n.exportSymbol("proto.botStatusRequest.gender_type",null,s),n.exportSymbol("proto.botStatusRequest.status_type",null,s),n.exportSymbol("proto.botStatusResponse",null,s),n.exportSymbol("proto.pInfo",null,s),n.exportSymbol("proto.welcome",null,s),proto.pInfo=function(t){r.Message.initialize(this,t,0,-1,null,null)},n.inherits(proto.pInfo,r.Message),n.DEBUG&&!COMPILED&&(proto.pInfo.displayName="proto.pInfo"),proto.botStatusRequest=function(t){r.Message.initialize(this,t,0,-1,null,null)},n.inherits(proto.botStatusRequest,r.Message),n.DEBUG&&!COMPILED&&(proto.botStatusRequest.displayName="proto.botStatusRequest"),proto.welcome=function(t){r.Message.initialize(this,t,0,-1,null,null)},n.inherits(proto.welcome,r.Message),n.DEBUG&&!COMPILED&&(proto.welcome.displayName="proto.welcome"),proto.botStatusResponse=function(t){r.Message.initialize(this,t,0,-1,proto.botStatusResponse.repeatedFields_,null)},n.inherits(proto.botStatusResponse,r.Message),n.DEBUG&&!COMPILED&&(proto.botStatusResponse.displayName="proto.botStatusResponse"),r.Message.GENERATE_TO_OBJECT&&(proto.pInfo.prototype.toObject=function(t){return proto.pInfo.toObject(t,this)},proto.pInfo.toObject=function(t,e){var o={cityId:r.Message.getFieldWithDefault(e,1,0),country:r.Message.getFieldWithDefault(e,2,""),region:r.Message.getFieldWithDefault(e,3,""),province:r.Message.getFieldWithDefault(e,4,""),city:r.Message.getFieldWithDefault(e,5,""),isp:r.Message.getFieldWithDefault(e,6,"")};return t&&(o.$jspbMessageInstance=e),o}),proto.pInfo.deserializeBinary=function(t){var e=new r.BinaryReader(t),o=new proto.pInfo;return proto.pInfo.deserializeBinaryFromReader(o,e)},proto.pInfo.deserializeBinaryFromReader=function(t,e){while(e.nextField()){if(e.isEndGroup())break;var o=e.getFieldNumber();switch(o){case 1:var r=e.readInt32();t.setCityId(r);break;case 2:r=e.readString();t.setCountry(r);break;case 3:r=e.readString();t.setRegion(r);break;case 4:r=e.readString();t.setProvince(r);break;case 5:r=e.readString();t.setCity(r);break;case 6:r=e.readString();t.setIsp(r);break;default:e.skipField();break}}return t},proto.pInfo.prototype.serializeBinary=function(){var t=new r.BinaryWriter;return proto.pInfo.serializeBinaryToWriter(this,t),t.getResultBuffer()},proto.pInfo.serializeBinaryToWriter=function(t,e){var o=void 0;o=t.getCityId(),0!==o&&e.writeInt32(1,o),o=t.getCountry(),o.length>0&&e.writeString(2,o),o=t.getRegion(),o.length>0&&e.writeString(3,o),o=t.getProvince(),o.length>0&&e.writeString(4,o),o=t.getCity(),o.length>0&&e.writeString(5,o),o=t.getIsp(),o.length>0&&e.writeString(6,o)},proto.pInfo.prototype.getCityId=function(){return r.Message.getFieldWithDefault(this,1,0)},proto.pInfo.prototype.setCityId=function(t){return r.Message.setProto3IntField(this,1,t)},proto.pInfo.prototype.getCountry=function(){return r.Message.getFieldWithDefault(this,2,"")},proto.pInfo.prototype.setCountry=function(t){return r.Message.setProto3StringField(this,2,t)},proto.pInfo.prototype.getRegion=function(){return r.Message.getFieldWithDefault(this,3,"")},proto.pInfo.prototype.setRegion=function(t){return r.Message.setProto3StringField(this,3,t)},proto.pInfo.prototype.getProvince=function(){return r.Message.getFieldWithDefault(this,4,"")},proto.pInfo.prototype.setProvince=function(t){return r.Message.setProto3StringField(this,4,t)},proto.pInfo.prototype.getCity=function(){return r.Message.getFieldWithDefault(this,5,"")},proto.pInfo.prototype.setCity=function(t){return r.Message.setProto3StringField(this,5,t)},proto.pInfo.prototype.getIsp=function(){return r.Message.getFieldWithDefault(this,6,"")},proto.pInfo.prototype.setIsp=function(t){return r.Message.setProto3StringField(this,6,t)},r.Message.GENERATE_TO_OBJECT&&(proto.botStatusRequest.prototype.toObject=function(t){return proto.botStatusRequest.toObject(t,this)},proto.botStatusRequest.toObject=function(t,e){var o,n={botId:r.Message.getFieldWithDefault(e,1,""),x:r.Message.getFloatingPointFieldWithDefault(e,2,0),y:r.Message.getFloatingPointFieldWithDefault(e,3,0),eyeX:r.Message.getFloatingPointFieldWithDefault(e,4,0),eyeY:r.Message.getFloatingPointFieldWithDefault(e,5,0),msg:r.Message.getFieldWithDefault(e,6,""),realX:r.Message.getFloatingPointFieldWithDefault(e,7,0),realY:r.Message.getFloatingPointFieldWithDefault(e,8,0),status:r.Message.getFieldWithDefault(e,9,0),name:r.Message.getFieldWithDefault(e,10,""),gender:r.Message.getFieldWithDefault(e,11,0),posInfo:(o=e.getPosInfo())&&proto.pInfo.toObject(t,o)};return t&&(n.$jspbMessageInstance=e),n}),proto.botStatusRequest.deserializeBinary=function(t){var e=new r.BinaryReader(t),o=new proto.botStatusRequest;return proto.botStatusRequest.deserializeBinaryFromReader(o,e)},proto.botStatusRequest.deserializeBinaryFromReader=function(t,e){while(e.nextField()){if(e.isEndGroup())break;var o=e.getFieldNumber();switch(o){case 1:var r=e.readString();t.setBotId(r);break;case 2:r=e.readFloat();t.setX(r);break;case 3:r=e.readFloat();t.setY(r);break;case 4:r=e.readFloat();t.setEyeX(r);break;case 5:r=e.readFloat();t.setEyeY(r);break;case 6:r=e.readString();t.setMsg(r);break;case 7:r=e.readFloat();t.setRealX(r);break;case 8:r=e.readFloat();t.setRealY(r);break;case 9:r=e.readEnum();t.setStatus(r);break;case 10:r=e.readString();t.setName(r);break;case 11:r=e.readEnum();t.setGender(r);break;case 12:r=new proto.pInfo;e.readMessage(r,proto.pInfo.deserializeBinaryFromReader),t.setPosInfo(r);break;default:e.skipField();break}}return t},proto.botStatusRequest.prototype.serializeBinary=function(){var t=new r.BinaryWriter;return proto.botStatusRequest.serializeBinaryToWriter(this,t),t.getResultBuffer()},proto.botStatusRequest.serializeBinaryToWriter=function(t,e){var o=void 0;o=t.getBotId(),o.length>0&&e.writeString(1,o),o=t.getX(),0!==o&&e.writeFloat(2,o),o=t.getY(),0!==o&&e.writeFloat(3,o),o=t.getEyeX(),0!==o&&e.writeFloat(4,o),o=t.getEyeY(),0!==o&&e.writeFloat(5,o),o=t.getMsg(),o.length>0&&e.writeString(6,o),o=t.getRealX(),0!==o&&e.writeFloat(7,o),o=t.getRealY(),0!==o&&e.writeFloat(8,o),o=t.getStatus(),0!==o&&e.writeEnum(9,o),o=t.getName(),o.length>0&&e.writeString(10,o),o=t.getGender(),0!==o&&e.writeEnum(11,o),o=t.getPosInfo(),null!=o&&e.writeMessage(12,o,proto.pInfo.serializeBinaryToWriter)},proto.botStatusRequest.status_type={WAITING:0,CONNECTING:1,CLOSE:2},proto.botStatusRequest.gender_type={MAN:0,WOMAN:1},proto.botStatusRequest.prototype.getBotId=function(){return r.Message.getFieldWithDefault(this,1,"")},proto.botStatusRequest.prototype.setBotId=function(t){return r.Message.setProto3StringField(this,1,t)},proto.botStatusRequest.prototype.getX=function(){return r.Message.getFloatingPointFieldWithDefault(this,2,0)},proto.botStatusRequest.prototype.setX=function(t){return r.Message.setProto3FloatField(this,2,t)},proto.botStatusRequest.prototype.getY=function(){return r.Message.getFloatingPointFieldWithDefault(this,3,0)},proto.botStatusRequest.prototype.setY=function(t){return r.Message.setProto3FloatField(this,3,t)},proto.botStatusRequest.prototype.getEyeX=function(){return r.Message.getFloatingPointFieldWithDefault(this,4,0)},proto.botStatusRequest.prototype.setEyeX=function(t){return r.Message.setProto3FloatField(this,4,t)},proto.botStatusRequest.prototype.getEyeY=function(){return r.Message.getFloatingPointFieldWithDefault(this,5,0)},proto.botStatusRequest.prototype.setEyeY=function(t){return r.Message.setProto3FloatField(this,5,t)},proto.botStatusRequest.prototype.getMsg=function(){return r.Message.getFieldWithDefault(this,6,"")},proto.botStatusRequest.prototype.setMsg=function(t){return r.Message.setProto3StringField(this,6,t)},proto.botStatusRequest.prototype.getRealX=function(){return r.Message.getFloatingPointFieldWithDefault(this,7,0)},proto.botStatusRequest.prototype.setRealX=function(t){return r.Message.setProto3FloatField(this,7,t)},proto.botStatusRequest.prototype.getRealY=function(){return r.Message.getFloatingPointFieldWithDefault(this,8,0)},proto.botStatusRequest.prototype.setRealY=function(t){return r.Message.setProto3FloatField(this,8,t)},proto.botStatusRequest.prototype.getStatus=function(){return r.Message.getFieldWithDefault(this,9,0)},proto.botStatusRequest.prototype.setStatus=function(t){return r.Message.setProto3EnumField(this,9,t)},proto.botStatusRequest.prototype.getName=function(){return r.Message.getFieldWithDefault(this,10,"")},proto.botStatusRequest.prototype.setName=function(t){return r.Message.setProto3StringField(this,10,t)},proto.botStatusRequest.prototype.getGender=function(){return r.Message.getFieldWithDefault(this,11,0)},proto.botStatusRequest.prototype.setGender=function(t){return r.Message.setProto3EnumField(this,11,t)},proto.botStatusRequest.prototype.getPosInfo=function(){return r.Message.getWrapperField(this,proto.pInfo,12)},proto.botStatusRequest.prototype.setPosInfo=function(t){return r.Message.setWrapperField(this,12,t)},proto.botStatusRequest.prototype.clearPosInfo=function(){return this.setPosInfo(void 0)},proto.botStatusRequest.prototype.hasPosInfo=function(){return null!=r.Message.getField(this,12)},proto.welcome.deserializeBinary=function(t){var e=new r.BinaryReader(t),o=new proto.welcome;return proto.welcome.deserializeBinaryFromReader(o,e)},proto.welcome.deserializeBinaryFromReader=function(t,e){while(e.nextField()){if(e.isEndGroup())break;var o=e.getFieldNumber();switch(o){case 1:var r=e.readString();t.setBotId(r);break;case 2:r=e.readString();t.setSessionToken(r);break;default:e.skipField();break}}return t},proto.welcome.prototype.getBotId=function(){return r.Message.getFieldWithDefault(this,1,"")},proto.welcome.prototype.setBotId=function(t){return r.Message.setProto3StringField(this,1,t)},proto.welcome.prototype.getSessionToken=function(){return r.Message.getFieldWithDefault(this,2,"")},proto.welcome.prototype.setSessionToken=function(t){return r.Message.setProto3StringField(this,2,t)},proto.botStatusResponse.repeatedFields_=[1],r.Message.GENERATE_TO_OBJECT&&(proto.botStatusResponse.prototype.toObject=function(t){return proto.botStatusResponse.toObject(t,this)},proto.botStatusResponse.toObject=function(t,e){var o={botStatusList:r.Message.toObjectList(e.getBotStatusList(),proto.botStatusRequest.toObject,t)};return t&&(o.$jspbMessageInstance=e),o}),proto.botStatusResponse.deserializeBinary=function(t){var e=new r.BinaryReader(t),o=new proto.botStatusResponse;return proto.botStatusResponse.deserializeBinaryFromReader(o,e)},proto.botStatusResponse.deserializeBinaryFromReader=function(t,e){while(e.nextField()){if(e.isEndGroup())break;var o=e.getFieldNumber();switch(o){case 1:var r=new proto.botStatusRequest;e.readMessage(r,proto.botStatusRequest.deserializeBinaryFromReader),t.addBotStatus(r);break;case 2:r=new proto.welcome;e.readMessage(r,proto.welcome.deserializeBinaryFromReader),t.setWelcome(r);break;default:e.skipField();break}}return t},proto.botStatusResponse.prototype.serializeBinary=function(){var t=new r.BinaryWriter;return proto.botStatusResponse.serializeBinaryToWriter(this,t),t.getResultBuffer()},proto.botStatusResponse.serializeBinaryToWriter=function(t,e){var o=void 0;o=t.getBotStatusList(),o.length>0&&e.writeRepeatedMessage(1,o,proto.botStatusRequest.serializeBinaryToWriter)},proto.botStatusResponse.prototype.getBotStatusList=function(){return r.Message.getRepeatedWrapperField(this,proto.botStatusRequest,1)},proto.botStatusResponse.prototype.setBotStatusList=function(t){return r.Message.setRepeatedWrapperField(this,1,t)},proto.botStatusResponse.prototype.addBotStatus=function(t,e){return r.Message.addToRepeatedWrapperField(this,1,t,proto.botStatusRequest,e)},proto.botStatusResponse.prototype.clearBotStatusList=function(){return this.setBotStatusList([])},proto.botStatusResponse.prototype.getWelcome=function(){return r.Message.getWrapperField(this,proto.welcome,2)},proto.botStatusResponse.prototype.setWelcome=function(t){return r.Message.setWrapperField(this,2,t)},proto.botStatusResponse.prototype.clearWelcome=function(){return this.setWelcome(void 0)},proto.botStatusResponse.prototype.hasWelcome=function(){return null!=r.Message.getField(this,2)},n.object.extend(e,proto)}});
+//# sourceMappingURL=app.2ab8e83d.js.map
\ No newline at end of file
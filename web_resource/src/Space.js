@@ -807,6 +807,12 @@ function createWebSocket() {
 
     ws.onmessage = function (evt) {
         var r = proto.botStatusResponse.deserializeBinary(evt.data)
+
+        // 服务端分配的 bot id，收到自己的状态时用它来区分自己和其他客户
+        if (r.hasWelcome()) {
+            bot_status.bot_id = r.getWelcome().getBotId();
+        }
+
         var bot_list = r.getBotStatusList();
 
         for (var i in bot_list) {
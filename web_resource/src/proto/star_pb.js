@@ -17,6 +17,7 @@ goog.exportSymbol('proto.botStatusRequest.gender_type', null, global);
 goog.exportSymbol('proto.botStatusRequest.status_type', null, global);
 goog.exportSymbol('proto.botStatusResponse', null, global);
 goog.exportSymbol('proto.pInfo', null, global);
+goog.exportSymbol('proto.welcome', null, global);
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -59,6 +60,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.botStatusRequest.displayName = 'proto.botStatusRequest';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.welcome = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.welcome, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.welcome.displayName = 'proto.welcome';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -859,6 +881,165 @@ proto.botStatusRequest.prototype.hasPosInfo = function() {
 
 
 
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.welcome.prototype.toObject = function(opt_includeInstance) {
+  return proto.welcome.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.welcome} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.welcome.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    botId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    sessionToken: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.welcome}
+ */
+proto.welcome.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.welcome;
+  return proto.welcome.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.welcome} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.welcome}
+ */
+proto.welcome.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBotId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSessionToken(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.welcome.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.welcome.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.welcome} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.welcome.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getBotId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getSessionToken();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string bot_id = 1;
+ * @return {string}
+ */
+proto.welcome.prototype.getBotId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.welcome} returns this
+ */
+proto.welcome.prototype.setBotId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string session_token = 2;
+ * @return {string}
+ */
+proto.welcome.prototype.getSessionToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.welcome} returns this
+ */
+proto.welcome.prototype.setSessionToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
 /**
  * List of repeated fields within this message type.
  * @private {!Array<number>}
@@ -898,7 +1079,8 @@ proto.botStatusResponse.prototype.toObject = function(opt_includeInstance) {
 proto.botStatusResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
     botStatusList: jspb.Message.toObjectList(msg.getBotStatusList(),
-    proto.botStatusRequest.toObject, includeInstance)
+    proto.botStatusRequest.toObject, includeInstance),
+    welcome: (f = msg.getWelcome()) && proto.welcome.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -940,6 +1122,11 @@ proto.botStatusResponse.deserializeBinaryFromReader = function(msg, reader) {
       reader.readMessage(value,proto.botStatusRequest.deserializeBinaryFromReader);
       msg.addBotStatus(value);
       break;
+    case 2:
+      var value = new proto.welcome;
+      reader.readMessage(value,proto.welcome.deserializeBinaryFromReader);
+      msg.setWelcome(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -977,6 +1164,14 @@ proto.botStatusResponse.serializeBinaryToWriter = function(message, writer) {
       proto.botStatusRequest.serializeBinaryToWriter
     );
   }
+  f = message.getWelcome();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.welcome.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -1018,4 +1213,41 @@ proto.botStatusResponse.prototype.clearBotStatusList = function() {
 };
 
 
+/**
+ * optional welcome welcome = 2;
+ * @return {?proto.welcome}
+ */
+proto.botStatusResponse.prototype.getWelcome = function() {
+  return /** @type{?proto.welcome} */ (
+    jspb.Message.getWrapperField(this, proto.welcome, 2));
+};
+
+
+/**
+ * @param {?proto.welcome|undefined} value
+ * @return {!proto.botStatusResponse} returns this
+*/
+proto.botStatusResponse.prototype.setWelcome = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.botStatusResponse} returns this
+ */
+proto.botStatusResponse.prototype.clearWelcome = function() {
+  return this.setWelcome(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.botStatusResponse.prototype.hasWelcome = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
 goog.object.extend(exports, proto);